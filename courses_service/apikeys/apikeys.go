@@ -0,0 +1,96 @@
+package apikeys
+
+import (
+	"context"
+	"courses_service/auth"
+	"courses_service/graph/model"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// keyPrefix identifica las API keys de este servicio, p. ej. en escáneres de secretos.
+const keyPrefix = "csk_"
+
+// Generate crea una API key aleatoria. Devuelve la key, que solo se muestra una
+// vez, y el hash que se guarda.
+func Generate() (key string, hash string, err error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", "", fmt.Errorf("Error generating API key: %v", err)
+	}
+	key = keyPrefix + base64.RawURLEncoding.EncodeToString(raw)
+	return key, Hash(key), nil
+}
+
+// Hash calcula el hash con el que se guarda y se busca una API key. Las keys
+// tienen 256 bits aleatorios, así que basta con SHA-256 sin sal.
+func Hash(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// Hint devuelve el inicio de la key, que permite reconocerla sin revelarla.
+func Hint(key string) string {
+	return key[:len(keyPrefix)+6] + "…"
+}
+
+// Store valida las API keys guardadas en la colección api_keys.
+type Store struct {
+	Collection *mongo.Collection
+}
+
+var _ auth.KeyAuthenticator = &Store{}
+
+// AuthenticateKey busca una key vigente y no revocada y, en la misma operación,
+// incrementa su contador de uso.
+func (s *Store) AuthenticateKey(ctx context.Context, key string) (*auth.Principal, error) {
+	if !strings.HasPrefix(key, keyPrefix) {
+		return nil, fmt.Errorf("malformed key")
+	}
+
+	now := time.Now().UTC().Format(time.RFC3339)
+	filter := bson.M{
+		"hash":      Hash(key),
+		"revokedat": nil,
+		"$or": bson.A{
+			bson.M{"expiresat": nil},
+			bson.M{"expiresat": bson.M{"$gt": now}},
+		},
+	}
+	update := bson.M{
+		"$inc": bson.M{"usagecount": 1},
+		"$set": bson.M{"lastusedat": now},
+	}
+
+	var apiKey model.APIKey
+	err := s.Collection.FindOneAndUpdate(ctx, filter, update,
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&apiKey)
+	if err == mongo.ErrNoDocuments {
+		return nil, fmt.Errorf("unknown, expired or revoked key")
+	}
+	if err != nil {
+		log.Printf("Failed to authenticate API key: %v", err)
+		return nil, fmt.Errorf("could not verify key")
+	}
+
+	roles := make([]string, 0, len(apiKey.Scopes))
+	for _, scope := range apiKey.Scopes {
+		roles = append(roles, string(scope))
+	}
+	return &auth.Principal{
+		UserID:   "apikey:" + apiKey.ID,
+		Roles:    roles,
+		APIKeyID: &apiKey.ID,
+	}, nil
+}
@@ -0,0 +1,98 @@
+package apperrors
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Code es el valor de extensions.code con el que se presenta un error.
+type Code string
+
+const (
+	CodeNotFound        Code = "NOT_FOUND"
+	CodeInvalidArgument Code = "INVALID_ARGUMENT"
+	CodeConflict        Code = "CONFLICT"
+	CodeUnauthenticated Code = "UNAUTHENTICATED"
+	CodeForbidden       Code = "FORBIDDEN"
+	CodeUnavailable     Code = "UNAVAILABLE"
+	CodeInternal        Code = "INTERNAL"
+)
+
+// Error es un error de dominio: su mensaje se muestra tal cual al cliente,
+// junto con el código y las extensiones.
+type Error struct {
+	Code       Code
+	Message    string
+	Extensions map[string]interface{}
+	// Err es la causa, que solo se registra en el log.
+	Err error
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// With agrega una extensión al error.
+func (e *Error) With(key string, value interface{}) *Error {
+	if e.Extensions == nil {
+		e.Extensions = map[string]interface{}{}
+	}
+	e.Extensions[key] = value
+	return e
+}
+
+// Wrap guarda la causa del error.
+func (e *Error) Wrap(err error) *Error {
+	e.Err = err
+	return e
+}
+
+func newError(code Code, format string, args ...interface{}) *Error {
+	return &Error{Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+// NotFound indica que el recurso pedido no existe.
+func NotFound(format string, args ...interface{}) *Error {
+	return newError(CodeNotFound, format, args...)
+}
+
+// InvalidArgument indica que los datos enviados por el cliente no son válidos.
+func InvalidArgument(format string, args ...interface{}) *Error {
+	return newError(CodeInvalidArgument, format, args...)
+}
+
+// Conflict indica que el cambio choca con el estado actual del recurso.
+func Conflict(format string, args ...interface{}) *Error {
+	return newError(CodeConflict, format, args...)
+}
+
+// Unauthenticated indica que la operación requiere credenciales válidas.
+func Unauthenticated(format string, args ...interface{}) *Error {
+	return newError(CodeUnauthenticated, format, args...)
+}
+
+// Forbidden indica que el usuario no puede hacer la operación.
+func Forbidden(format string, args ...interface{}) *Error {
+	return newError(CodeForbidden, format, args...)
+}
+
+// Unavailable indica que una dependencia no responde; el cliente puede reintentar.
+func Unavailable(format string, args ...interface{}) *Error {
+	return newError(CodeUnavailable, format, args...)
+}
+
+// CodeOf devuelve el código de un error de dominio, o "" si no lo es.
+func CodeOf(err error) Code {
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return appErr.Code
+	}
+	return ""
+}
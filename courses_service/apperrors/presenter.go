@@ -0,0 +1,108 @@
+package apperrors
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"runtime/debug"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/streadway/amqp"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/x/mongo/driver/topology"
+)
+
+// Presenter es el ErrorPresenter del servidor GraphQL. Los errores de dominio
+// se muestran con su código; los que ya genera gqlgen (de validación, de
+// límites, etc.) se devuelven como están; los demás se ocultan detrás de un
+// error INTERNAL o UNAVAILABLE con un traceID que permite encontrarlos en el log.
+func Presenter(ctx context.Context, err error) *gqlerror.Error {
+	presented := graphql.DefaultErrorPresenter(ctx, err)
+
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return present(presented, appErr)
+	}
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return present(presented, NotFound("not found"))
+	}
+
+	// Errores de gqlgen o creados a propósito para el cliente
+	var gqlErr *gqlerror.Error
+	if errors.As(err, &gqlErr) && (gqlErr.Err == nil || gqlErr.Extensions["code"] != nil) {
+		return presented
+	}
+
+	traceID := newTraceID()
+	if isUnavailable(err) {
+		log.Printf("Unavailable error [%s] at %s: %v", traceID, presented.Path, err)
+		return present(presented, Unavailable("service temporarily unavailable, try again later").With("traceID", traceID))
+	}
+	log.Printf("Internal error [%s] at %s: %v", traceID, presented.Path, err)
+	return present(presented, internal(traceID))
+}
+
+// Recover es el RecoverFunc del servidor GraphQL: registra el panic con su
+// stack y devuelve un error INTERNAL con el mismo traceID.
+func Recover(ctx context.Context, p interface{}) error {
+	traceID := newTraceID()
+	log.Printf("Panic [%s] at %s: %v\n%s", traceID, graphql.GetPath(ctx), p, debug.Stack())
+	return internal(traceID)
+}
+
+func internal(traceID string) *Error {
+	return newError(CodeInternal, "internal error").With("traceID", traceID)
+}
+
+// present copia el mensaje y las extensiones del error de dominio, conservando
+// la ruta y la ubicación del error presentado.
+func present(presented *gqlerror.Error, appErr *Error) *gqlerror.Error {
+	extensions := map[string]interface{}{}
+	for key, value := range appErr.Extensions {
+		extensions[key] = value
+	}
+	extensions["code"] = string(appErr.Code)
+
+	return &gqlerror.Error{
+		Err:        appErr,
+		Message:    appErr.Message,
+		Path:       presented.Path,
+		Locations:  presented.Locations,
+		Extensions: extensions,
+	}
+}
+
+// isUnavailable reconoce los errores de conexión con MongoDB y RabbitMQ y los
+// tiempos de espera agotados.
+func isUnavailable(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, mongo.ErrClientDisconnected) || errors.Is(err, amqp.ErrClosed) {
+		return true
+	}
+	if mongo.IsTimeout(err) || mongo.IsNetworkError(err) {
+		return true
+	}
+	var selectionErr topology.ServerSelectionError
+	if errors.As(err, &selectionErr) {
+		return true
+	}
+	var amqpErr *amqp.Error
+	if errors.As(err, &amqpErr) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+func newTraceID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%016x", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,146 @@
+package apperrors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/streadway/amqp"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestPresenter(t *testing.T) {
+	ctx := graphql.WithPathContext(context.Background(), graphql.NewPathWithField("course"))
+
+	tests := []struct {
+		name        string
+		err         error
+		wantCode    string
+		wantMessage string
+		wantTraceID bool
+	}{
+		{
+			name:        "domain error",
+			err:         NotFound("course %s not found", "c1"),
+			wantCode:    "NOT_FOUND",
+			wantMessage: "course c1 not found",
+		},
+		{
+			name:        "wrapped domain error",
+			err:         fmt.Errorf("Error loading course: %w", Conflict("version mismatch")),
+			wantCode:    "CONFLICT",
+			wantMessage: "version mismatch",
+		},
+		{
+			name:        "domain error hides its cause",
+			err:         InvalidArgument("invalid price").Wrap(errors.New("strconv: parsing")),
+			wantCode:    "INVALID_ARGUMENT",
+			wantMessage: "invalid price",
+		},
+		{
+			name:        "no documents",
+			err:         fmt.Errorf("Error finding course: %w", mongo.ErrNoDocuments),
+			wantCode:    "NOT_FOUND",
+			wantMessage: "not found",
+		},
+		{
+			name:        "deadline exceeded",
+			err:         fmt.Errorf("Error finding course: %w", context.DeadlineExceeded),
+			wantCode:    "UNAVAILABLE",
+			wantMessage: "service temporarily unavailable, try again later",
+			wantTraceID: true,
+		},
+		{
+			name:        "broker closed",
+			err:         fmt.Errorf("Error publishing event: %w", amqp.ErrClosed),
+			wantCode:    "UNAVAILABLE",
+			wantMessage: "service temporarily unavailable, try again later",
+			wantTraceID: true,
+		},
+		{
+			name:        "unexpected error",
+			err:         errors.New("mongo: secret connection string"),
+			wantCode:    "INTERNAL",
+			wantMessage: "internal error",
+			wantTraceID: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Presenter(ctx, tt.err)
+			if got.Message != tt.wantMessage {
+				t.Errorf("Presenter() message = %q, want %q", got.Message, tt.wantMessage)
+			}
+			if code := got.Extensions["code"]; code != tt.wantCode {
+				t.Errorf("Presenter() code = %v, want %s", code, tt.wantCode)
+			}
+			if traceID, _ := got.Extensions["traceID"].(string); (traceID != "") != tt.wantTraceID {
+				t.Errorf("Presenter() traceID = %q, want traceID %v", traceID, tt.wantTraceID)
+			}
+			if got.Path.String() != "course" {
+				t.Errorf("Presenter() path = %q, want %q", got.Path.String(), "course")
+			}
+		})
+	}
+}
+
+func TestPresenterKeepsExtensions(t *testing.T) {
+	err := Conflict("version mismatch").With("currentVersion", 3)
+
+	got := Presenter(context.Background(), err)
+	if got.Extensions["currentVersion"] != 3 || got.Extensions["code"] != "CONFLICT" {
+		t.Errorf("Presenter() extensions = %v, want currentVersion and code", got.Extensions)
+	}
+	if _, ok := err.Extensions["code"]; ok {
+		t.Errorf("Presenter() modified the extensions of the domain error")
+	}
+}
+
+func TestPresenterGraphQLErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		err  *gqlerror.Error
+	}{
+		{
+			name: "validation error",
+			err:  gqlerror.Errorf("Cannot query field \"foo\" on type \"Query\"."),
+		},
+		{
+			name: "error with its own code",
+			err: &gqlerror.Error{
+				Err:        errors.New("operation has complexity 2000, which exceeds the limit of 1000"),
+				Message:    "operation has complexity 2000, which exceeds the limit of 1000",
+				Extensions: map[string]interface{}{"code": "COMPLEXITY_LIMIT_EXCEEDED"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Presenter(context.Background(), tt.err); got != tt.err {
+				t.Errorf("Presenter() = %v, want the error unchanged", got)
+			}
+		})
+	}
+}
+
+func TestRecover(t *testing.T) {
+	err := Recover(context.Background(), "index out of range")
+
+	if code := CodeOf(err); code != CodeInternal {
+		t.Fatalf("Recover() code = %s, want %s", code, CodeInternal)
+	}
+	if strings.Contains(err.Error(), "index out of range") {
+		t.Errorf("Recover() = %q, must not expose the panic value", err.Error())
+	}
+
+	presented := Presenter(context.Background(), err)
+	if presented.Extensions["code"] != "INTERNAL" || presented.Extensions["traceID"] == nil {
+		t.Errorf("Presenter(Recover()) extensions = %v, want code INTERNAL and a traceID", presented.Extensions)
+	}
+}
@@ -0,0 +1,257 @@
+package audit
+
+import (
+	"context"
+	"courses_service/auth"
+	"courses_service/graph/model"
+	"encoding/json"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/vektah/gqlparser/v2/ast"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// maxResultSize limita el tamaño del resultado que se guarda de cada mutación.
+const maxResultSize = 4096
+
+// writeTimeout es el tiempo máximo para guardar una entrada; no depende del
+// contexto de la petición para que se registre aunque el cliente se desconecte.
+const writeTimeout = 5 * time.Second
+
+// writeAttempts es la cantidad de intentos para guardar las entradas de una operación.
+const writeAttempts = 3
+
+// queueSize es la cantidad máxima de operaciones cuyas entradas esperan a ser
+// guardadas. Si la cola está llena, las entradas se escriben en el log del
+// servicio en vez de demorar la respuesta.
+const queueSize = 1000
+
+// Logger es una extensión de gqlgen que guarda en la colección de auditoría una
+// entrada por cada campo de mutación ejecutado. La colección solo recibe
+// inserciones: el servicio nunca modifica ni borra entradas. Las entradas se
+// guardan en segundo plano; se crea con NewLogger.
+type Logger struct {
+	Collection *mongo.Collection
+
+	mu     sync.RWMutex
+	closed bool
+	queue  chan []interface{}
+	done   chan struct{}
+}
+
+// NewLogger crea el Logger e inicia el escritor que guarda las entradas.
+func NewLogger(collection *mongo.Collection) *Logger {
+	l := &Logger{
+		Collection: collection,
+		queue:      make(chan []interface{}, queueSize),
+		done:       make(chan struct{}),
+	}
+	go l.run()
+	return l
+}
+
+// run guarda las entradas de la cola, con sus reintentos, hasta que se cierra.
+func (l *Logger) run() {
+	defer close(l.done)
+	for entries := range l.queue {
+		l.insert(entries)
+	}
+}
+
+// Close deja de aceptar entradas y espera a que se guarden las pendientes.
+func (l *Logger) Close() {
+	l.mu.Lock()
+	if !l.closed {
+		l.closed = true
+		close(l.queue)
+	}
+	l.mu.Unlock()
+	<-l.done
+}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.ResponseInterceptor
+} = &Logger{}
+
+func (l *Logger) ExtensionName() string {
+	return "AuditLog"
+}
+
+func (l *Logger) Validate(schema graphql.ExecutableSchema) error {
+	return nil
+}
+
+func (l *Logger) InterceptResponse(ctx context.Context, next graphql.ResponseHandler) *graphql.Response {
+	if !graphql.HasOperationContext(ctx) {
+		return next(ctx)
+	}
+	oc := graphql.GetOperationContext(ctx)
+	if oc.Operation == nil || oc.Operation.Operation != ast.Mutation {
+		return next(ctx)
+	}
+
+	response := next(ctx)
+	l.record(ctx, oc, response)
+	return response
+}
+
+// record guarda una entrada por cada campo de la mutación con sus argumentos,
+// su resultado y los errores que produjo.
+func (l *Logger) record(ctx context.Context, oc *graphql.OperationContext, response *graphql.Response) {
+	var data map[string]json.RawMessage
+	if response != nil && len(response.Data) > 0 {
+		json.Unmarshal(response.Data, &data)
+	}
+
+	var operationName *string
+	if oc.OperationName != "" {
+		operationName = &oc.OperationName
+	}
+	now := time.Now().UTC().Format(time.RFC3339)
+
+	entries := []interface{}{}
+	for _, field := range graphql.CollectFields(oc, oc.Operation.SelectionSet, []string{"Mutation"}) {
+		if field.Name == "__typename" {
+			continue
+		}
+
+		entry := model.AuditEntry{
+			ID:            primitive.NewObjectID().Hex(),
+			Actor:         Actor(ctx),
+			Operation:     field.Name,
+			OperationName: operationName,
+			Arguments:     marshal(Redact(field.ArgumentMap(oc.Variables))),
+			Errors:        fieldErrors(response, field.Alias),
+			IP:            ClientIP(ctx),
+			Timestamp:     now,
+		}
+		entry.Success = len(entry.Errors) == 0
+		if result, ok := data[field.Alias]; ok && string(result) != "null" {
+			entry.Result = redactJSON(result)
+		}
+		entries = append(entries, entry)
+	}
+	if len(entries) == 0 {
+		return
+	}
+
+	l.enqueue(entries)
+}
+
+// enqueue pasa las entradas al escritor sin esperar. Si la cola está llena o el
+// Logger ya se cerró, se escriben en el log del servicio para que no se pierdan.
+func (l *Logger) enqueue(entries []interface{}) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	if !l.closed {
+		select {
+		case l.queue <- entries:
+			return
+		default:
+		}
+	}
+	log.Printf("Audit queue unavailable, logging %d entries instead", len(entries))
+	logUnsaved(entries)
+}
+
+// insert guarda las entradas y reintenta las que fallaron. Si después de
+// writeAttempts intentos alguna sigue sin guardarse, se escribe en el log del
+// servicio para que no se pierda.
+func (l *Logger) insert(entries []interface{}) {
+	var err error
+	for attempt := 1; attempt <= writeAttempts; attempt++ {
+		entries, err = l.insertMany(entries)
+		if err == nil {
+			return
+		}
+		log.Printf("Failed to write %d audit entries (attempt %d/%d): %v", len(entries), attempt, writeAttempts, err)
+		if attempt < writeAttempts {
+			time.Sleep(time.Duration(attempt) * 100 * time.Millisecond)
+		}
+	}
+	logUnsaved(entries)
+}
+
+func logUnsaved(entries []interface{}) {
+	for _, entry := range entries {
+		log.Printf("Unsaved audit entry: %s", marshal(entry))
+	}
+}
+
+// insertMany guarda las entradas y devuelve las que no se pudieron guardar. Las
+// que ya existían (de un intento anterior) cuentan como guardadas.
+func (l *Logger) insertMany(entries []interface{}) ([]interface{}, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), writeTimeout)
+	defer cancel()
+
+	_, err := l.Collection.InsertMany(ctx, entries, options.InsertMany().SetOrdered(false))
+	if err == nil {
+		return nil, nil
+	}
+	bulkErr, ok := err.(mongo.BulkWriteException)
+	if !ok || bulkErr.WriteConcernError != nil {
+		return entries, err
+	}
+
+	failed := []interface{}{}
+	for _, writeErr := range bulkErr.WriteErrors {
+		if !mongo.IsDuplicateKeyError(writeErr) {
+			failed = append(failed, entries[writeErr.Index])
+		}
+	}
+	if len(failed) == 0 {
+		return nil, nil
+	}
+	return failed, err
+}
+
+// Actor identifica al usuario autenticado que ejecuta la operación.
+func Actor(ctx context.Context) string {
+	if principal := auth.ForContext(ctx); principal != nil {
+		return principal.UserID
+	}
+	return "anonymous"
+}
+
+// fieldErrors devuelve los mensajes de los errores producidos por un campo.
+func fieldErrors(response *graphql.Response, alias string) []string {
+	messages := []string{}
+	if response == nil {
+		return messages
+	}
+	for _, err := range response.Errors {
+		if len(err.Path) == 0 || err.Path[0] == ast.PathName(alias) {
+			messages = append(messages, err.Message)
+		}
+	}
+	return messages
+}
+
+// redactJSON oculta los campos sensibles de un resultado y lo recorta a maxResultSize.
+func redactJSON(raw json.RawMessage) *string {
+	var value interface{}
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return nil
+	}
+	result := marshal(Redact(value))
+	if len(result) > maxResultSize {
+		result = result[:maxResultSize] + "…"
+	}
+	return &result
+}
+
+func marshal(value interface{}) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		log.Printf("Error marshaling audit value: %v", err)
+		return "null"
+	}
+	return string(data)
+}
@@ -0,0 +1,98 @@
+package audit
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// TrustedProxies son las redes de los proxies cuyo X-Forwarded-For se acepta.
+type TrustedProxies []*net.IPNet
+
+// LoadTrustedProxies lee de TRUSTED_PROXIES la lista, separada por comas, de
+// IPs o redes CIDR de los proxies delante del servicio. Sin la variable no se
+// confía en ningún proxy y se usa siempre la dirección de la conexión.
+func LoadTrustedProxies() (TrustedProxies, error) {
+	var proxies TrustedProxies
+	for _, part := range strings.Split(os.Getenv("TRUSTED_PROXIES"), ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		// Una IP suelta equivale a una red con solo esa dirección
+		cidr := part
+		if !strings.Contains(cidr, "/") {
+			if strings.Contains(cidr, ":") {
+				cidr += "/128"
+			} else {
+				cidr += "/32"
+			}
+		}
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid TRUSTED_PROXIES entry %q", part)
+		}
+		proxies = append(proxies, network)
+	}
+	return proxies, nil
+}
+
+// Contains indica si la IP pertenece a algún proxy de confianza.
+func (p TrustedProxies) Contains(ip string) bool {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+	for _, network := range p {
+		if network.Contains(parsed) {
+			return true
+		}
+	}
+	return false
+}
+
+// ClientAddr devuelve la IP del cliente de la petición. X-Forwarded-For solo se
+// tiene en cuenta si la conexión viene de un proxy de confianza: se recorre de
+// derecha a izquierda y se toma la primera dirección que no es de un proxy,
+// porque las anteriores las pudo escribir el propio cliente.
+func (p TrustedProxies) ClientAddr(r *http.Request) string {
+	ip := r.RemoteAddr
+	if host, _, err := net.SplitHostPort(ip); err == nil {
+		ip = host
+	}
+	if !p.Contains(ip) {
+		return ip
+	}
+
+	hops := strings.Split(r.Header.Get("X-Forwarded-For"), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(hops[i])
+		if hop == "" {
+			continue
+		}
+		if !p.Contains(hop) {
+			return hop
+		}
+		ip = hop
+	}
+	return ip
+}
+
+type contextKey struct{}
+
+// Middleware guarda en el contexto la IP del cliente, calculada con ClientAddr.
+func Middleware(proxies TrustedProxies, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ip := proxies.ClientAddr(r)
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), contextKey{}, ip)))
+	})
+}
+
+// ClientIP devuelve la IP guardada por Middleware.
+func ClientIP(ctx context.Context) string {
+	ip, _ := ctx.Value(contextKey{}).(string)
+	return ip
+}
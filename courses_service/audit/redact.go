@@ -0,0 +1,66 @@
+package audit
+
+import (
+	"strings"
+
+	"github.com/99designs/gqlgen/graphql"
+)
+
+// redacted sustituye el valor de los campos sensibles.
+const redacted = "[REDACTED]"
+
+// sensitiveKeys son fragmentos de nombres de campo cuyo valor no se guarda.
+var sensitiveKeys = []string{
+	"password",
+	"secret",
+	"token",
+	"apikey",
+	"authorization",
+	"credential",
+}
+
+// Redact devuelve una copia del valor con los campos sensibles ocultos. Los
+// archivos subidos se reducen a su nombre, tamaño y tipo.
+func Redact(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			if isSensitive(key) {
+				result[key] = redacted
+			} else {
+				result[key] = Redact(item)
+			}
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(v))
+		for i, item := range v {
+			result[i] = Redact(item)
+		}
+		return result
+	case graphql.Upload:
+		return map[string]interface{}{
+			"filename":    v.Filename,
+			"size":        v.Size,
+			"contentType": v.ContentType,
+		}
+	case *graphql.Upload:
+		if v == nil {
+			return nil
+		}
+		return Redact(*v)
+	default:
+		return v
+	}
+}
+
+func isSensitive(key string) bool {
+	key = strings.ToLower(strings.NewReplacer("_", "", "-", "").Replace(key))
+	for _, sensitive := range sensitiveKeys {
+		if strings.Contains(key, sensitive) {
+			return true
+		}
+	}
+	return false
+}
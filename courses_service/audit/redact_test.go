@@ -0,0 +1,96 @@
+package audit
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/99designs/gqlgen/graphql"
+)
+
+func TestRedact(t *testing.T) {
+	upload := graphql.Upload{Filename: "cover.png", Size: 1234, ContentType: "image/png"}
+	uploadSummary := map[string]interface{}{"filename": "cover.png", "size": int64(1234), "contentType": "image/png"}
+
+	tests := []struct {
+		name  string
+		value interface{}
+		want  interface{}
+	}{
+		{
+			name:  "plain values",
+			value: map[string]interface{}{"title": "Go", "price": 10.5},
+			want:  map[string]interface{}{"title": "Go", "price": 10.5},
+		},
+		{
+			name: "sensitive keys",
+			value: map[string]interface{}{
+				"password":      "hunter2",
+				"clientSecret":  "s3cr3t",
+				"access_token":  "abc",
+				"API-Key":       "key",
+				"Authorization": "Bearer x",
+				"credentials":   map[string]interface{}{"user": "u"},
+				"title":         "Go",
+			},
+			want: map[string]interface{}{
+				"password":      redacted,
+				"clientSecret":  redacted,
+				"access_token":  redacted,
+				"API-Key":       redacted,
+				"Authorization": redacted,
+				"credentials":   redacted,
+				"title":         "Go",
+			},
+		},
+		{
+			name: "nested maps and lists",
+			value: map[string]interface{}{
+				"input": map[string]interface{}{
+					"accounts": []interface{}{
+						map[string]interface{}{"name": "a", "token": "t1"},
+						map[string]interface{}{"name": "b", "token": "t2"},
+					},
+				},
+			},
+			want: map[string]interface{}{
+				"input": map[string]interface{}{
+					"accounts": []interface{}{
+						map[string]interface{}{"name": "a", "token": redacted},
+						map[string]interface{}{"name": "b", "token": redacted},
+					},
+				},
+			},
+		},
+		{
+			name:  "uploads",
+			value: map[string]interface{}{"file": upload, "files": []interface{}{&upload}},
+			want:  map[string]interface{}{"file": uploadSummary, "files": []interface{}{uploadSummary}},
+		},
+		{
+			name:  "nil upload",
+			value: map[string]interface{}{"file": (*graphql.Upload)(nil)},
+			want:  map[string]interface{}{"file": nil},
+		},
+		{
+			name:  "scalar",
+			value: "password",
+			want:  "password",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Redact(tt.value); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Redact() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRedactDoesNotModifyInput(t *testing.T) {
+	value := map[string]interface{}{"password": "hunter2"}
+	Redact(value)
+	if value["password"] != "hunter2" {
+		t.Errorf("Redact modified its input: %v", value)
+	}
+}
@@ -0,0 +1,190 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Principal es el usuario autenticado que hace la petición.
+type Principal struct {
+	UserID string   `json:"userID"`
+	Roles  []string `json:"roles"`
+	// APIKeyID es la API key con la que se autenticó, si no se usó un token.
+	APIKeyID *string `json:"apiKeyID"`
+}
+
+// NormalizeRole devuelve el nombre canónico de un rol, en mayúsculas como los
+// valores del enum Role, para que "admin" y "ADMIN" sean el mismo rol.
+func NormalizeRole(role string) string {
+	return strings.ToUpper(strings.TrimSpace(role))
+}
+
+// Claims son los claims que se leen del token: el usuario va en sub.
+type Claims struct {
+	Roles []string `json:"roles"`
+	jwt.RegisteredClaims
+}
+
+// Authenticator valida tokens JWT firmados con HS256 o RS256.
+type Authenticator struct {
+	hmacSecret []byte
+	rsaKeys    map[string]interface{}
+	options    []jwt.ParserOption
+}
+
+// LoadFromEnv crea el autenticador a partir de JWT_HS256_SECRET y/o JWT_JWKS_FILE
+// (archivo JWKS con las claves públicas RSA). JWT_ISSUER y JWT_AUDIENCE, si
+// están definidos, se exigen en los tokens. Devuelve nil si no hay claves configuradas.
+func LoadFromEnv() (*Authenticator, error) {
+	a := &Authenticator{}
+
+	if secret := os.Getenv("JWT_HS256_SECRET"); secret != "" {
+		if len(secret) < 32 {
+			return nil, fmt.Errorf("JWT_HS256_SECRET must be at least 32 characters")
+		}
+		a.hmacSecret = []byte(secret)
+	}
+
+	if path := os.Getenv("JWT_JWKS_FILE"); path != "" {
+		keys, err := ReadJWKSFile(path)
+		if err != nil {
+			return nil, err
+		}
+		a.rsaKeys = keys
+	}
+
+	if a.hmacSecret == nil && len(a.rsaKeys) == 0 {
+		return nil, nil
+	}
+
+	a.options = []jwt.ParserOption{
+		jwt.WithValidMethods([]string{"HS256", "RS256"}),
+		jwt.WithExpirationRequired(),
+	}
+	if issuer := os.Getenv("JWT_ISSUER"); issuer != "" {
+		a.options = append(a.options, jwt.WithIssuer(issuer))
+	}
+	if audience := os.Getenv("JWT_AUDIENCE"); audience != "" {
+		a.options = append(a.options, jwt.WithAudience(audience))
+	}
+	return a, nil
+}
+
+// Authenticate valida el token y devuelve el usuario que contiene.
+func (a *Authenticator) Authenticate(tokenString string) (*Principal, error) {
+	if a == nil {
+		return nil, fmt.Errorf("token authentication is not configured")
+	}
+
+	var claims Claims
+	_, err := jwt.ParseWithClaims(tokenString, &claims, a.key, a.options...)
+	if err != nil {
+		return nil, err
+	}
+	if claims.Subject == "" {
+		return nil, fmt.Errorf("token has no subject")
+	}
+
+	roles := claims.Roles
+	if roles == nil {
+		roles = []string{}
+	}
+	return &Principal{UserID: claims.Subject, Roles: roles}, nil
+}
+
+// key elige la clave de verificación según el algoritmo y el kid del token.
+func (a *Authenticator) key(token *jwt.Token) (interface{}, error) {
+	switch token.Method.Alg() {
+	case "HS256":
+		if a.hmacSecret == nil {
+			return nil, fmt.Errorf("HS256 tokens are not accepted")
+		}
+		return a.hmacSecret, nil
+	case "RS256":
+		kid, _ := token.Header["kid"].(string)
+		if key, ok := a.rsaKeys[kid]; ok {
+			return key, nil
+		}
+		// Un token sin kid se acepta si solo hay una clave
+		if kid == "" && len(a.rsaKeys) == 1 {
+			for _, key := range a.rsaKeys {
+				return key, nil
+			}
+		}
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	default:
+		return nil, fmt.Errorf("unexpected signing method %s", token.Method.Alg())
+	}
+}
+
+type contextKey struct{}
+
+// KeyAuthenticator valida las API keys de los servicios que llaman sin token de usuario.
+type KeyAuthenticator interface {
+	AuthenticateKey(ctx context.Context, key string) (*Principal, error)
+}
+
+// Middleware autentica el token Bearer del encabezado Authorization o, si no
+// hay, la API key del encabezado X-API-Key, y guarda el usuario en el contexto.
+// Las peticiones sin credenciales siguen como anónimas; las que traen
+// credenciales inválidas se rechazan con 401.
+func Middleware(tokens *Authenticator, keys KeyAuthenticator, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var principal *Principal
+		var err error
+
+		if header := r.Header.Get("Authorization"); header != "" {
+			token, ok := strings.CutPrefix(header, "Bearer ")
+			if !ok {
+				unauthorized(w, "Authorization header must use the Bearer scheme")
+				return
+			}
+			principal, err = tokens.Authenticate(strings.TrimSpace(token))
+			if err != nil {
+				unauthorized(w, fmt.Sprintf("invalid token: %v", err))
+				return
+			}
+		} else if key := r.Header.Get("X-API-Key"); key != "" {
+			principal, err = keys.AuthenticateKey(r.Context(), strings.TrimSpace(key))
+			if err != nil {
+				unauthorized(w, fmt.Sprintf("invalid API key: %v", err))
+				return
+			}
+		}
+
+		if principal != nil {
+			r = r.WithContext(WithPrincipal(r.Context(), principal))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// unauthorized responde con un error en el formato de GraphQL.
+func unauthorized(w http.ResponseWriter, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("WWW-Authenticate", "Bearer")
+	w.WriteHeader(http.StatusUnauthorized)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"errors": []map[string]interface{}{{
+			"message":    message,
+			"extensions": map[string]string{"code": "UNAUTHENTICATED"},
+		}},
+	})
+}
+
+// WithPrincipal guarda el usuario autenticado en el contexto.
+func WithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, contextKey{}, principal)
+}
+
+// ForContext devuelve el usuario autenticado, o nil si la petición es anónima.
+func ForContext(ctx context.Context) *Principal {
+	principal, _ := ctx.Value(contextKey{}).(*Principal)
+	return principal
+}
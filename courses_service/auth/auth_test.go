@@ -0,0 +1,129 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func TestAuthenticate(t *testing.T) {
+	secret := []byte("0123456789abcdef0123456789abcdef")
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claims := func() Claims {
+		return Claims{
+			Roles: []string{"ADMIN"},
+			RegisteredClaims: jwt.RegisteredClaims{
+				Subject:   "user-1",
+				ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+			},
+		}
+	}
+	sign := func(method jwt.SigningMethod, kid string, key interface{}, c Claims) string {
+		token := jwt.NewWithClaims(method, c)
+		if kid != "" {
+			token.Header["kid"] = kid
+		}
+		signed, err := token.SignedString(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return signed
+	}
+	options := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{"HS256", "RS256"}),
+		jwt.WithExpirationRequired(),
+	}
+
+	both := &Authenticator{hmacSecret: secret, rsaKeys: map[string]interface{}{"k1": &rsaKey.PublicKey}, options: options}
+	rsaOnly := &Authenticator{rsaKeys: map[string]interface{}{"k1": &rsaKey.PublicKey}, options: options}
+	twoKeys := &Authenticator{rsaKeys: map[string]interface{}{"k1": &rsaKey.PublicKey, "k2": &otherKey.PublicKey}, options: options}
+
+	expired := claims()
+	expired.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-time.Hour))
+	noExpiry := claims()
+	noExpiry.ExpiresAt = nil
+	noSubject := claims()
+	noSubject.Subject = ""
+
+	tests := []struct {
+		name  string
+		auth  *Authenticator
+		token string
+		ok    bool
+	}{
+		{"HS256 with secret", both, sign(jwt.SigningMethodHS256, "", secret, claims()), true},
+		{"HS256 without secret", rsaOnly, sign(jwt.SigningMethodHS256, "", secret, claims()), false},
+		{"HS256 with wrong secret", both, sign(jwt.SigningMethodHS256, "", []byte("another secret of thirty-two bytes!"), claims()), false},
+		{"HS256 signed with the RSA public key", rsaOnly, sign(jwt.SigningMethodHS256, "k1", x509PublicKey(t, &rsaKey.PublicKey), claims()), false},
+		{"RS256 with kid", both, sign(jwt.SigningMethodRS256, "k1", rsaKey, claims()), true},
+		{"RS256 with unknown kid", both, sign(jwt.SigningMethodRS256, "k9", rsaKey, claims()), false},
+		{"RS256 signed with another key", twoKeys, sign(jwt.SigningMethodRS256, "k2", rsaKey, claims()), false},
+		{"RS256 without kid and one key", rsaOnly, sign(jwt.SigningMethodRS256, "", rsaKey, claims()), true},
+		{"RS256 without kid and several keys", twoKeys, sign(jwt.SigningMethodRS256, "", rsaKey, claims()), false},
+		{"RS384 is not accepted", rsaOnly, sign(jwt.SigningMethodRS384, "k1", rsaKey, claims()), false},
+		{"none is not accepted", both, sign(jwt.SigningMethodNone, "", jwt.UnsafeAllowNoneSignatureType, claims()), false},
+		{"expired", both, sign(jwt.SigningMethodHS256, "", secret, expired), false},
+		{"without expiration", both, sign(jwt.SigningMethodHS256, "", secret, noExpiry), false},
+		{"without subject", both, sign(jwt.SigningMethodHS256, "", secret, noSubject), false},
+		{"not configured", nil, sign(jwt.SigningMethodHS256, "", secret, claims()), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			principal, err := tt.auth.Authenticate(tt.token)
+			if tt.ok {
+				if err != nil {
+					t.Fatalf("Authenticate() error = %v", err)
+				}
+				if principal.UserID != "user-1" || len(principal.Roles) != 1 || principal.Roles[0] != "ADMIN" {
+					t.Errorf("Authenticate() = %+v", principal)
+				}
+				return
+			}
+			if err == nil {
+				t.Errorf("Authenticate() = %+v, want error", principal)
+			}
+		})
+	}
+}
+
+// x509PublicKey devuelve la clave pública en PEM, como la usaría un atacante
+// que firma un token HS256 con la clave pública de RS256.
+func x509PublicKey(t *testing.T, key *rsa.PublicKey) []byte {
+	t.Helper()
+	der, err := x509.MarshalPKIXPublicKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+}
+
+func TestNormalizeRole(t *testing.T) {
+	tests := []struct {
+		role string
+		want string
+	}{
+		{"ADMIN", "ADMIN"},
+		{"admin", "ADMIN"},
+		{" Instructor ", "INSTRUCTOR"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := NormalizeRole(tt.role); got != tt.want {
+			t.Errorf("NormalizeRole(%q) = %q, want %q", tt.role, got, tt.want)
+		}
+	}
+}
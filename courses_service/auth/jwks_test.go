@@ -0,0 +1,85 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReadJWKSFile(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	n := base64.RawURLEncoding.EncodeToString(key.PublicKey.N.Bytes())
+	e := base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.PublicKey.E)).Bytes())
+	rsaKey := func(kid, use, alg string) string {
+		return `{"kty":"RSA","kid":"` + kid + `","use":"` + use + `","alg":"` + alg + `","n":"` + n + `","e":"` + e + `"}`
+	}
+	set := func(keys ...string) string {
+		return `{"keys":[` + strings.Join(keys, ",") + `]}`
+	}
+
+	tests := []struct {
+		name    string
+		content string
+		kids    []string
+		wantErr bool
+	}{
+		{"one key", set(rsaKey("k1", "sig", "RS256")), []string{"k1"}, false},
+		{"use and alg are optional", set(rsaKey("k1", "", "")), []string{"k1"}, false},
+		{"several keys", set(rsaKey("k1", "sig", "RS256"), rsaKey("k2", "sig", "RS256")), []string{"k1", "k2"}, false},
+		{"skips encryption keys", set(rsaKey("k1", "enc", "RS256"), rsaKey("k2", "sig", "RS256")), []string{"k2"}, false},
+		{"skips other algorithms", set(rsaKey("k1", "sig", "RS512"), rsaKey("k2", "sig", "RS256")), []string{"k2"}, false},
+		{"skips other key types", set(`{"kty":"EC","kid":"e1","crv":"P-256"}`, rsaKey("k2", "sig", "RS256")), []string{"k2"}, false},
+		{"no usable keys", set(`{"kty":"EC","kid":"e1"}`), nil, true},
+		{"empty set", set(), nil, true},
+		{"invalid modulus", set(`{"kty":"RSA","kid":"k1","n":"***","e":"` + e + `"}`), nil, true},
+		{"invalid exponent", set(`{"kty":"RSA","kid":"k1","n":"` + n + `","e":""}`), nil, true},
+		{"exponent too large", set(`{"kty":"RSA","kid":"k1","n":"` + n + `","e":"AQAAAAAB"}`), nil, true},
+		{"invalid JSON", `{"keys":`, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "jwks.json")
+			if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			keys, err := ReadJWKSFile(path)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ReadJWKSFile() = %v, want error", keys)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ReadJWKSFile() error = %v", err)
+			}
+			if len(keys) != len(tt.kids) {
+				t.Fatalf("ReadJWKSFile() returned %d keys, want %d", len(keys), len(tt.kids))
+			}
+			for _, kid := range tt.kids {
+				public, ok := keys[kid].(*rsa.PublicKey)
+				if !ok {
+					t.Fatalf("key %q missing or not RSA: %T", kid, keys[kid])
+				}
+				if public.N.Cmp(key.PublicKey.N) != 0 || public.E != key.PublicKey.E {
+					t.Errorf("key %q does not match the generated key", kid)
+				}
+			}
+		})
+	}
+}
+
+func TestReadJWKSFileMissing(t *testing.T) {
+	if _, err := ReadJWKSFile(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("ReadJWKSFile() of a missing file did not fail")
+	}
+}
@@ -0,0 +1,47 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+)
+
+// WebsocketInit autentica las conexiones de suscripción con el payload de
+// connection_init, ya que los navegadores no permiten encabezados propios en
+// un websocket. Acepta "Authorization" ("Bearer <token>") o "authToken" para
+// JWT y "apiKey" o "X-API-Key" para API keys. Sin credenciales la conexión
+// sigue como anónima.
+func WebsocketInit(tokens *Authenticator, keys KeyAuthenticator) transport.WebsocketInitFunc {
+	return func(ctx context.Context, payload transport.InitPayload) (context.Context, *transport.InitPayload, error) {
+		token := strings.TrimPrefix(payload.Authorization(), "Bearer ")
+		if token == "" {
+			token = payload.GetString("authToken")
+		}
+		key := payload.GetString("apiKey")
+		if key == "" {
+			key = payload.GetString("X-API-Key")
+		}
+
+		var principal *Principal
+		var err error
+		switch {
+		case token != "":
+			principal, err = tokens.Authenticate(strings.TrimSpace(token))
+			if err != nil {
+				return ctx, nil, fmt.Errorf("invalid token: %v", err)
+			}
+		case key != "":
+			principal, err = keys.AuthenticateKey(ctx, strings.TrimSpace(key))
+			if err != nil {
+				return ctx, nil, fmt.Errorf("invalid API key: %v", err)
+			}
+		default:
+			// Puede venir autenticada por los encabezados de la petición de upgrade
+			return ctx, &payload, nil
+		}
+
+		return WithPrincipal(ctx, principal), &payload, nil
+	}
+}
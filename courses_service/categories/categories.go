@@ -0,0 +1,89 @@
+package categories
+
+import (
+	"context"
+	"courses_service/graph/model"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var accents = strings.NewReplacer(
+	"á", "a", "à", "a", "ä", "a", "â", "a",
+	"é", "e", "è", "e", "ë", "e", "ê", "e",
+	"í", "i", "ì", "i", "ï", "i", "î", "i",
+	"ó", "o", "ò", "o", "ö", "o", "ô", "o",
+	"ú", "u", "ù", "u", "ü", "u", "û", "u",
+	"ñ", "n", "ç", "c",
+)
+
+// Slugify convierte un nombre en un slug en minúsculas separado por guiones,
+// p. ej. "Diseño Web" -> "diseno-web".
+func Slugify(name string) string {
+	name = accents.Replace(strings.ToLower(strings.TrimSpace(name)))
+
+	var b strings.Builder
+	dash := false
+	for _, r := range name {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			dash = false
+		} else {
+			dash = true
+		}
+	}
+	return b.String()
+}
+
+// MatchKey reduce un nombre a letras y dígitos para tratar como la misma
+// categoría variantes como "Backend", "backend" y "Back-end".
+func MatchKey(name string) string {
+	return strings.ReplaceAll(Slugify(name), "-", "")
+}
+
+// FetchAll carga todas las categorías ordenadas por posición.
+func FetchAll(ctx context.Context, collection *mongo.Collection) ([]*model.Category, error) {
+	cursor, err := collection.Find(ctx, bson.D{}, options.Find().SetSort(bson.D{
+		{Key: "position", Value: 1},
+		{Key: "name", Value: 1},
+	}))
+	if err != nil {
+		return nil, fmt.Errorf("Error finding categories: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	all := []*model.Category{}
+	if err := cursor.All(ctx, &all); err != nil {
+		return nil, fmt.Errorf("Error decoding categories: %v", err)
+	}
+	return all, nil
+}
+
+// Descendants devuelve el ID indicado junto con los de todas sus subcategorías.
+func Descendants(all []*model.Category, id string) []string {
+	children := map[string][]string{}
+	for _, c := range all {
+		if c.ParentID != nil {
+			children[*c.ParentID] = append(children[*c.ParentID], c.ID)
+		}
+	}
+
+	ids := []string{id}
+	seen := map[string]bool{id: true}
+	for i := 0; i < len(ids); i++ {
+		for _, child := range children[ids[i]] {
+			if !seen[child] {
+				seen[child] = true
+				ids = append(ids, child)
+			}
+		}
+	}
+	return ids
+}
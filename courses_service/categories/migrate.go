@@ -0,0 +1,95 @@
+package categories
+
+import (
+	"context"
+	"courses_service/graph/model"
+	"fmt"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// MigrationReport resume lo que hizo (o haría, en modo dry run) la migración.
+type MigrationReport struct {
+	CreatedCategories []string
+	// Mapped asocia cada texto de categoría encontrado con el slug asignado.
+	Mapped         map[string]string
+	UpdatedCourses int64
+}
+
+// Migrate convierte el campo de texto category de los cursos en referencias a la
+// colección de categorías. Los textos que coinciden por MatchKey se agrupan en una
+// sola categoría; aliases permite forzar el slug de un texto concreto. Los cursos
+// conservan el texto original, por lo que la migración puede repetirse.
+func Migrate(ctx context.Context, courses *mongo.Collection, categories *mongo.Collection, aliases map[string]string, dryRun bool) (*MigrationReport, error) {
+	values, err := courses.Distinct(ctx, "category", bson.M{
+		"category":   bson.M{"$type": "string"},
+		"categoryid": nil,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Error listing course categories: %v", err)
+	}
+
+	existing, err := FetchAll(ctx, categories)
+	if err != nil {
+		return nil, err
+	}
+	byKey := map[string]*model.Category{}
+	for _, c := range existing {
+		byKey[MatchKey(c.Slug)] = c
+		byKey[MatchKey(c.Name)] = c
+	}
+
+	report := &MigrationReport{Mapped: map[string]string{}}
+	for _, v := range values {
+		text, ok := v.(string)
+		if !ok || Slugify(text) == "" {
+			continue
+		}
+
+		key := MatchKey(text)
+		if alias, ok := aliases[text]; ok {
+			key = MatchKey(alias)
+		}
+
+		category, ok := byKey[key]
+		if !ok {
+			slug := Slugify(text)
+			if alias, ok := aliases[text]; ok {
+				slug = Slugify(alias)
+			}
+			category = &model.Category{
+				ID:        primitive.NewObjectID().Hex(),
+				Slug:      slug,
+				Name:      text,
+				CreatedAt: time.Now().Format(time.RFC3339),
+			}
+			if !dryRun {
+				if _, err := categories.InsertOne(ctx, category); err != nil {
+					return nil, fmt.Errorf("Error creating category %s: %v", slug, err)
+				}
+			}
+			byKey[key] = category
+			report.CreatedCategories = append(report.CreatedCategories, slug)
+		}
+		report.Mapped[text] = category.Slug
+
+		if dryRun {
+			continue
+		}
+		result, err := courses.UpdateMany(ctx,
+			bson.M{"category": text, "categoryid": nil},
+			bson.M{"$set": bson.M{"categoryid": category.ID}},
+		)
+		if err != nil {
+			return nil, fmt.Errorf("Error updating courses with category %q: %v", text, err)
+		}
+		report.UpdatedCourses += result.ModifiedCount
+		log.Printf("Mapped category %q to %s (%d courses)", text, category.Slug, result.ModifiedCount)
+	}
+
+	return report, nil
+}
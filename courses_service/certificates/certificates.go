@@ -0,0 +1,145 @@
+package certificates
+
+import (
+	"courses_service/graph/model"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Signer firma y verifica el contenido de los certificados.
+type Signer interface {
+	Algorithm() string
+	Sign(payload []byte) []byte
+	Verify(payload []byte, signature []byte) bool
+}
+
+// HMACSigner firma con HMAC-SHA256 y una clave secreta compartida.
+type HMACSigner struct {
+	key []byte
+}
+
+func NewHMACSigner(key []byte) *HMACSigner {
+	return &HMACSigner{key: key}
+}
+
+func (s *HMACSigner) Algorithm() string {
+	return "HMAC-SHA256"
+}
+
+func (s *HMACSigner) Sign(payload []byte) []byte {
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
+
+func (s *HMACSigner) Verify(payload []byte, signature []byte) bool {
+	return hmac.Equal(s.Sign(payload), signature)
+}
+
+// Ed25519Signer firma con una clave privada Ed25519, de modo que terceros pueden
+// verificar los certificados solo con la clave pública.
+type Ed25519Signer struct {
+	key ed25519.PrivateKey
+}
+
+func NewEd25519Signer(seed []byte) (*Ed25519Signer, error) {
+	if len(seed) != ed25519.SeedSize {
+		return nil, fmt.Errorf("Ed25519 seed must be %d bytes, got %d", ed25519.SeedSize, len(seed))
+	}
+	return &Ed25519Signer{key: ed25519.NewKeyFromSeed(seed)}, nil
+}
+
+func (s *Ed25519Signer) Algorithm() string {
+	return "Ed25519"
+}
+
+func (s *Ed25519Signer) Sign(payload []byte) []byte {
+	return ed25519.Sign(s.key, payload)
+}
+
+func (s *Ed25519Signer) Verify(payload []byte, signature []byte) bool {
+	return ed25519.Verify(s.key.Public().(ed25519.PublicKey), payload, signature)
+}
+
+// LoadSigner crea el firmador a partir de la configuración. CERTIFICATE_ED25519_SEED
+// (semilla de 32 bytes en base64) tiene prioridad sobre CERTIFICATE_HMAC_SECRET.
+// Devuelve nil si no hay ninguna clave configurada.
+func LoadSigner() (Signer, error) {
+	if seed := os.Getenv("CERTIFICATE_ED25519_SEED"); seed != "" {
+		raw, err := base64.StdEncoding.DecodeString(seed)
+		if err != nil {
+			return nil, fmt.Errorf("Error decoding CERTIFICATE_ED25519_SEED: %v", err)
+		}
+		signer, err := NewEd25519Signer(raw)
+		if err != nil {
+			return nil, err
+		}
+		return signer, nil
+	}
+
+	if secret := os.Getenv("CERTIFICATE_HMAC_SECRET"); secret != "" {
+		if len(secret) < 32 {
+			return nil, fmt.Errorf("CERTIFICATE_HMAC_SECRET must be at least 32 characters")
+		}
+		return NewHMACSigner([]byte(secret)), nil
+	}
+
+	return nil, nil
+}
+
+// NewCode genera un código de verificación aleatorio con la forma XXXX-XXXX-XXXX-XXXX.
+func NewCode() (string, error) {
+	raw := make([]byte, 10)
+	if _, err := rand.Read(raw); err != nil {
+		return "", fmt.Errorf("Error generating certificate code: %v", err)
+	}
+
+	encoded := base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(raw)
+	groups := make([]string, 0, len(encoded)/4)
+	for i := 0; i < len(encoded); i += 4 {
+		groups = append(groups, encoded[i:i+4])
+	}
+	return strings.Join(groups, "-"), nil
+}
+
+// NormalizeCode acepta el código con minúsculas o espacios alrededor.
+func NormalizeCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// payload es el contenido firmado; cualquier cambio en estos campos invalida la firma.
+func payload(cert *model.Certificate) []byte {
+	return []byte(strings.Join([]string{
+		cert.Code,
+		cert.EnrollmentID,
+		cert.CourseID,
+		cert.CourseTitle,
+		cert.UserID,
+		cert.IssuedAt,
+	}, "|"))
+}
+
+// Sign calcula la firma del certificado y la guarda en base64.
+func Sign(signer Signer, cert *model.Certificate) {
+	cert.Algorithm = signer.Algorithm()
+	cert.Signature = base64.StdEncoding.EncodeToString(signer.Sign(payload(cert)))
+}
+
+// Verify comprueba que la firma corresponde al contenido del certificado.
+func Verify(signer Signer, cert *model.Certificate) bool {
+	if cert.Algorithm != signer.Algorithm() {
+		return false
+	}
+	signature, err := base64.StdEncoding.DecodeString(cert.Signature)
+	if err != nil {
+		return false
+	}
+	return signer.Verify(payload(cert), signature)
+}
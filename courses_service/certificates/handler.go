@@ -0,0 +1,52 @@
+package certificates
+
+import (
+	"courses_service/graph/model"
+	"log"
+	"net/http"
+	"path"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// PathPrefix es la ruta bajo la que se sirven los certificados, p. ej. /certificates/ABCD-EFGH-IJKL-MNOP.pdf
+const PathPrefix = "/certificates/"
+
+// DownloadURL devuelve la ruta de descarga del certificado en el formato indicado.
+func DownloadURL(cert *model.Certificate, format model.CertificateFormat) string {
+	return PathPrefix + cert.Code + "." + strings.ToLower(format.String())
+}
+
+// Handler sirve los certificados como PDF o SVG según la extensión pedida.
+func Handler(collection *mongo.Collection) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		name := strings.TrimPrefix(req.URL.Path, PathPrefix)
+		ext := path.Ext(name)
+		code := NormalizeCode(strings.TrimSuffix(name, ext))
+
+		var cert model.Certificate
+		err := collection.FindOne(req.Context(), bson.M{"code": code}).Decode(&cert)
+		if err == mongo.ErrNoDocuments {
+			http.NotFound(w, req)
+			return
+		}
+		if err != nil {
+			log.Printf("Failed to find certificate %s: %v", code, err)
+			http.Error(w, "internal error", http.StatusInternalServerError)
+			return
+		}
+
+		switch ext {
+		case ".pdf":
+			w.Header().Set("Content-Type", "application/pdf")
+			w.Write(RenderPDF(&cert))
+		case ".svg":
+			w.Header().Set("Content-Type", "image/svg+xml")
+			w.Write(RenderSVG(&cert))
+		default:
+			http.NotFound(w, req)
+		}
+	})
+}
@@ -0,0 +1,102 @@
+package certificates
+
+import (
+	"bytes"
+	"courses_service/graph/model"
+	"fmt"
+	"html"
+	"strings"
+)
+
+// Tamaño de página A4 apaisada en puntos
+const (
+	pageWidth  = 842
+	pageHeight = 595
+)
+
+// RenderSVG dibuja el certificado como una imagen SVG.
+func RenderSVG(cert *model.Certificate) []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`, pageWidth, pageHeight, pageWidth, pageHeight)
+	fmt.Fprintf(&b, `<rect x="20" y="20" width="%d" height="%d" fill="#ffffff" stroke="#1f3a5f" stroke-width="4"/>`, pageWidth-40, pageHeight-40)
+	for _, line := range lines(cert) {
+		fmt.Fprintf(&b, `<text x="%d" y="%d" font-family="Helvetica, Arial, sans-serif" font-size="%d" text-anchor="middle" fill="#1f3a5f">%s</text>`,
+			pageWidth/2, pageHeight-line.y, line.size, html.EscapeString(line.text))
+	}
+	b.WriteString(`</svg>`)
+	return b.Bytes()
+}
+
+// RenderPDF genera un PDF de una página con el texto del certificado.
+func RenderPDF(cert *model.Certificate) []byte {
+	var content bytes.Buffer
+	content.WriteString("0.12 0.23 0.37 RG 4 w 20 20 802 555 re S\n")
+	for _, line := range lines(cert) {
+		// Centrado aproximado: Helvetica mide en promedio medio em por carácter
+		x := float64(pageWidth)/2 - float64(len(line.text)*line.size)/4
+		fmt.Fprintf(&content, "BT /F1 %d Tf %.1f %d Td (%s) Tj ET\n", line.size, x, line.y, pdfString(line.text))
+	}
+
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] /Resources << /Font << /F1 4 0 R >> >> /Contents 5 0 R >>", pageWidth, pageHeight),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>",
+		fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", content.Len(), content.String()),
+	}
+
+	var b bytes.Buffer
+	b.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, obj := range objects {
+		offsets[i] = b.Len()
+		fmt.Fprintf(&b, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+
+	xref := b.Len()
+	fmt.Fprintf(&b, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&b, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&b, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+	return b.Bytes()
+}
+
+type textLine struct {
+	text string
+	size int
+	y    int
+}
+
+// lines devuelve el texto del certificado; y se mide desde el borde inferior.
+func lines(cert *model.Certificate) []textLine {
+	return []textLine{
+		{text: "Certificate of Completion", size: 36, y: 470},
+		{text: "This certifies that user", size: 16, y: 400},
+		{text: cert.UserID, size: 24, y: 365},
+		{text: "has completed the course", size: 16, y: 320},
+		{text: cert.CourseTitle, size: 26, y: 280},
+		{text: "Issued on " + cert.IssuedAt, size: 14, y: 200},
+		{text: "Verification code: " + cert.Code, size: 14, y: 120},
+	}
+}
+
+// pdfString escapa el texto para un literal de PDF y lo codifica en WinAnsi;
+// los caracteres fuera de Latin-1 se reemplazan por '?'.
+func pdfString(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteByte(byte(r))
+		case r < 0x20:
+			b.WriteByte(' ')
+		case r < 0x100:
+			b.WriteByte(byte(r))
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,244 @@
+package changestream
+
+import (
+	"context"
+	"courses_service/graph/model"
+	"courses_service/rabbitmq"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	// leaseDuration es cuánto dura el permiso para vigilar la colección si la
+	// instancia que lo tiene deja de renovarlo.
+	leaseDuration = 30 * time.Second
+	renewInterval = 10 * time.Second
+	retryInterval = 5 * time.Second
+)
+
+// Códigos de error de MongoDB que indican que el resume token ya no está en el oplog.
+var historyLostCodes = []int{
+	280, // ChangeStreamFatalError
+	286, // ChangeStreamHistoryLost
+}
+
+// Watcher convierte las inserciones, actualizaciones y borrados de la colección
+// courses en eventos de dominio publicados en RabbitMQ, incluidos los cambios
+// hechos directamente en MongoDB. Tras publicar cada evento guarda el resume
+// token, así que al reiniciar continúa donde se quedó; un evento puede
+// publicarse dos veces si el servicio se detiene entre ambas operaciones.
+//
+// Solo una instancia del servicio vigila la colección a la vez: la que tiene el
+// permiso (lease) guardado en el documento de estado.
+type Watcher struct {
+	Courses *mongo.Collection
+	// State guarda el resume token y el permiso, en el documento con _id Name.
+	State *mongo.Collection
+	Name  string
+	// Owner identifica a esta instancia en el permiso.
+	Owner string
+}
+
+// state es el documento de la colección change_streams.
+type state struct {
+	ID          string   `bson:"_id"`
+	ResumeToken bson.Raw `bson:"resumetoken,omitempty"`
+	Owner       string   `bson:"owner"`
+	LeaseUntil  string   `bson:"leaseuntil"`
+}
+
+// NewWatcher crea el watcher de la colección courses. El dueño del permiso se
+// identifica con el nombre del host y el PID.
+func NewWatcher(courses *mongo.Collection, stateCollection *mongo.Collection) *Watcher {
+	host, _ := os.Hostname()
+	return &Watcher{
+		Courses: courses,
+		State:   stateCollection,
+		Name:    courses.Name(),
+		Owner:   host + ":" + strconv.Itoa(os.Getpid()),
+	}
+}
+
+// Run vigila la colección mientras esta instancia tenga el permiso; si no lo
+// tiene, o si se pierde la conexión, lo vuelve a intentar tras una pausa.
+func (w *Watcher) Run(ctx context.Context) {
+	for ctx.Err() == nil {
+		acquired, err := w.acquireLease(ctx)
+		if err != nil {
+			log.Printf("Failed to acquire change stream lease for %s: %v", w.Name, err)
+		}
+		if acquired {
+			if err := w.watch(ctx); err != nil && ctx.Err() == nil {
+				log.Printf("Change stream on %s stopped: %v", w.Name, err)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+		case <-time.After(retryInterval):
+		}
+	}
+}
+
+// acquireLease toma el permiso si está libre, vencido o ya es de esta instancia.
+func (w *Watcher) acquireLease(ctx context.Context) (bool, error) {
+	now := time.Now().UTC()
+	_, err := w.State.UpdateOne(ctx,
+		bson.M{"_id": w.Name, "$or": bson.A{
+			bson.M{"owner": w.Owner},
+			bson.M{"leaseuntil": bson.M{"$lt": now.Format(time.RFC3339)}},
+		}},
+		bson.M{"$set": bson.M{
+			"owner":      w.Owner,
+			"leaseuntil": now.Add(leaseDuration).Format(time.RFC3339),
+		}},
+		options.Update().SetUpsert(true),
+	)
+	// Si otra instancia tiene el permiso, el filtro no coincide y el upsert choca con su _id
+	if mongo.IsDuplicateKeyError(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// watch procesa el change stream hasta que falla o se pierde el permiso.
+func (w *Watcher) watch(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Renovar el permiso mientras se vigila; si no se puede, otra instancia
+	// podría tomarlo y hay que dejar de publicar
+	go func() {
+		ticker := time.NewTicker(renewInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				acquired, err := w.acquireLease(ctx)
+				if err != nil || !acquired {
+					log.Printf("Lost change stream lease for %s: %v", w.Name, err)
+					cancel()
+					return
+				}
+			}
+		}
+	}()
+
+	var current state
+	err := w.State.FindOne(ctx, bson.M{"_id": w.Name}).Decode(&current)
+	if err != nil {
+		return fmt.Errorf("Error reading change stream state: %v", err)
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"operationType": bson.M{"$in": bson.A{"insert", "update", "replace", "delete"}}}}},
+	}
+	streamOptions := options.ChangeStream().SetFullDocument(options.UpdateLookup)
+	if len(current.ResumeToken) > 0 {
+		streamOptions.SetResumeAfter(current.ResumeToken)
+	}
+
+	stream, err := w.Courses.Watch(ctx, pipeline, streamOptions)
+	if isHistoryLost(err) {
+		// El token es demasiado antiguo: se descarta y se empieza desde ahora
+		log.Printf("Resume token for %s is no longer available, starting from the current time", w.Name)
+		if err := w.saveToken(ctx, nil); err != nil {
+			return err
+		}
+		stream, err = w.Courses.Watch(ctx, pipeline, options.ChangeStream().SetFullDocument(options.UpdateLookup))
+	}
+	if err != nil {
+		return fmt.Errorf("Error opening change stream: %v", err)
+	}
+	defer stream.Close(context.Background())
+
+	log.Printf("Watching changes on %s", w.Name)
+	for stream.Next(ctx) {
+		var change change
+		if err := stream.Decode(&change); err != nil {
+			// Un documento que no se puede decodificar fallaría en cada reintento:
+			// se registra y se avanza el token para no quedarse atascado en él
+			log.Printf("Skipping undecodable change on %s: %v: %s", w.Name, err, stream.Current)
+		} else if err := publish(change); err != nil {
+			// No se guarda el token para volver a intentar este cambio
+			return err
+		}
+		if err := w.saveToken(ctx, stream.ResumeToken()); err != nil {
+			return err
+		}
+	}
+	return stream.Err()
+}
+
+func (w *Watcher) saveToken(ctx context.Context, token bson.Raw) error {
+	update := bson.M{"$set": bson.M{"resumetoken": token}}
+	if token == nil {
+		update = bson.M{"$unset": bson.M{"resumetoken": ""}}
+	}
+	_, err := w.State.UpdateOne(ctx, bson.M{"_id": w.Name, "owner": w.Owner}, update)
+	if err != nil {
+		return fmt.Errorf("Error saving resume token: %v", err)
+	}
+	return nil
+}
+
+func isHistoryLost(err error) bool {
+	var serverErr mongo.ServerError
+	if !errors.As(err, &serverErr) {
+		return false
+	}
+	for _, code := range historyLostCodes {
+		if serverErr.HasErrorCode(code) {
+			return true
+		}
+	}
+	return false
+}
+
+// change es la parte del evento del change stream que se usa.
+type change struct {
+	OperationType string `bson:"operationType"`
+	DocumentKey   struct {
+		ID string `bson:"_id"`
+	} `bson:"documentKey"`
+	FullDocument *model.Course `bson:"fullDocument"`
+}
+
+// publish publica el evento de dominio que corresponde a un cambio.
+func publish(c change) error {
+	var eventType string
+	var data interface{}
+
+	switch c.OperationType {
+	case "insert":
+		eventType, data = rabbitmq.CourseCreatedEvent, c.FullDocument
+	case "update", "replace":
+		// Si el curso se borró antes de leerlo no hay documento; el borrado llegará después
+		if c.FullDocument == nil {
+			return nil
+		}
+		eventType, data = rabbitmq.CourseUpdatedEvent, c.FullDocument
+	case "delete":
+		eventType, data = rabbitmq.CourseDeletedEvent, map[string]string{"id": c.DocumentKey.ID}
+	default:
+		return nil
+	}
+
+	if err := rabbitmq.PublishEvent(eventType, data); err != nil {
+		return fmt.Errorf("Error publishing %s event for course %s: %v", eventType, c.DocumentKey.ID, err)
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+// migrate-categories convierte el texto libre Course.category en referencias a la
+// colección categories. Uso:
+//
+//	go run ./cmd/migrate-categories [-dry-run] [-aliases aliases.json]
+//
+// aliases.json asocia textos con el slug que deben recibir, p. ej. {"Back-end": "backend"}.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"log"
+	"os"
+	"time"
+
+	"courses_service/categories"
+
+	"github.com/joho/godotenv"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func main() {
+	dryRun := flag.Bool("dry-run", false, "mostrar los cambios sin aplicarlos")
+	aliasesFile := flag.String("aliases", "", "archivo JSON con alias de categorías")
+	flag.Parse()
+
+	// Cargar las variables de entorno desde el archivo .env, si existe
+	_ = godotenv.Load()
+
+	aliases := map[string]string{}
+	if *aliasesFile != "" {
+		data, err := os.ReadFile(*aliasesFile)
+		if err != nil {
+			log.Fatalf("Error reading aliases file: %v", err)
+		}
+		if err := json.Unmarshal(data, &aliases); err != nil {
+			log.Fatalf("Error parsing aliases file: %v", err)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(os.Getenv("MONGO_URI")))
+	if err != nil {
+		log.Fatalf("Error connecting to MongoDB: %v", err)
+	}
+	defer client.Disconnect(context.Background())
+
+	db := client.Database("coursesDB")
+	report, err := categories.Migrate(ctx, db.Collection("courses"), db.Collection("categories"), aliases, *dryRun)
+	if err != nil {
+		log.Fatalf("Migration failed: %v", err)
+	}
+
+	for text, slug := range report.Mapped {
+		log.Printf("%q -> %s", text, slug)
+	}
+	log.Printf("Created %d categories, updated %d courses (dry run: %v)",
+		len(report.CreatedCategories), report.UpdatedCourses, *dryRun)
+}
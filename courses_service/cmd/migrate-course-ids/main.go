@@ -0,0 +1,80 @@
+// migrate-course-ids guarda el ID de los cursos en _id como texto. Los cursos
+// creados antes tienen un _id ObjectID generado por MongoDB y el ID que ven los
+// clientes en el campo id, así que las búsquedas por _id no los encuentran.
+// Cada curso se reescribe con _id igual a su antiguo id (o al hexadecimal del
+// _id si no tenía) y sin el campo id. Puede repetirse sin efecto. Uso:
+//
+//	go run ./cmd/migrate-course-ids [-dry-run]
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"time"
+
+	"github.com/joho/godotenv"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func main() {
+	dryRun := flag.Bool("dry-run", false, "mostrar los cambios sin aplicarlos")
+	flag.Parse()
+
+	// Cargar las variables de entorno desde el archivo .env, si existe
+	_ = godotenv.Load()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(os.Getenv("MONGO_URI")))
+	if err != nil {
+		log.Fatalf("Error connecting to MongoDB: %v", err)
+	}
+	defer client.Disconnect(context.Background())
+
+	courses := client.Database("coursesDB").Collection("courses")
+	cursor, err := courses.Find(ctx, bson.M{"_id": bson.M{"$type": "objectId"}})
+	if err != nil {
+		log.Fatalf("Error finding courses: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	migrated := 0
+	for cursor.Next(ctx) {
+		var doc bson.M
+		if err := cursor.Decode(&doc); err != nil {
+			log.Fatalf("Error decoding course: %v", err)
+		}
+		oldID := doc["_id"].(primitive.ObjectID)
+		newID := oldID.Hex()
+		if id, ok := doc["id"].(string); ok && id != "" {
+			newID = id
+		}
+		log.Printf("%s -> %s", oldID.Hex(), newID)
+		migrated++
+		if *dryRun {
+			continue
+		}
+
+		// _id no se puede modificar: se inserta la copia y después se borra el original.
+		// Si una ejecución anterior ya insertó la copia, solo falta el borrado.
+		delete(doc, "id")
+		doc["_id"] = newID
+		if _, err := courses.InsertOne(ctx, doc); err != nil && !mongo.IsDuplicateKeyError(err) {
+			log.Fatalf("Error inserting course %s: %v", newID, err)
+		}
+		if _, err := courses.DeleteOne(ctx, bson.M{"_id": oldID}); err != nil {
+			log.Fatalf("Error deleting course %s: %v", oldID.Hex(), err)
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		log.Fatalf("Error reading courses: %v", err)
+	}
+
+	log.Printf("Migrated %d courses (dry run: %v)", migrated, *dryRun)
+}
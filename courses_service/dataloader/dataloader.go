@@ -0,0 +1,191 @@
+package dataloader
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrNotFound es el error de las claves que la función de carga no devolvió.
+var ErrNotFound = errors.New("not found")
+
+// BatchFunc carga varias claves en una sola consulta. Las claves que no
+// existen simplemente no aparecen en el mapa.
+type BatchFunc[K comparable, V any] func(ctx context.Context, keys []K) (map[K]V, error)
+
+// Loader agrupa las cargas pedidas durante una ventana corta en una sola
+// llamada a la función de carga y guarda los resultados mientras dure la
+// petición. Se crea uno por petición: la caché nunca se invalida sola.
+type Loader[K comparable, V any] struct {
+	ctx      context.Context
+	name     string
+	fetch    BatchFunc[K, V]
+	wait     time.Duration
+	maxBatch int
+
+	mu      sync.Mutex
+	cache   map[K]*result[V]
+	pending *batch[K, V]
+}
+
+type result[V any] struct {
+	done  chan struct{}
+	value V
+	err   error
+}
+
+type batch[K comparable, V any] struct {
+	keys    []K
+	results []*result[V]
+	// once evita consultar dos veces un lote que se llenó antes de que venciera su espera.
+	once sync.Once
+}
+
+// Options configura la ventana de agrupación de un Loader.
+type Options struct {
+	// Wait es cuánto se espera a otras claves antes de consultar.
+	Wait time.Duration
+	// MaxBatch consulta en cuanto se juntan tantas claves; 0 no tiene límite.
+	MaxBatch int
+}
+
+// DefaultOptions alcanza para juntar las claves que piden los resolvers de una
+// misma lista, que gqlgen ejecuta en paralelo.
+var DefaultOptions = Options{Wait: 2 * time.Millisecond, MaxBatch: 100}
+
+// New crea un Loader. ctx es el contexto de la petición y se usa para las
+// consultas, de modo que una carga no falla porque se canceló el resolver que
+// abrió el lote. name identifica al Loader en las métricas.
+func New[K comparable, V any](ctx context.Context, name string, fetch BatchFunc[K, V], opts Options) *Loader[K, V] {
+	return &Loader[K, V]{
+		ctx:      ctx,
+		name:     name,
+		fetch:    fetch,
+		wait:     opts.Wait,
+		maxBatch: opts.MaxBatch,
+		cache:    map[K]*result[V]{},
+	}
+}
+
+// Load devuelve el valor de una clave, o ErrNotFound si no existe.
+func (l *Loader[K, V]) Load(ctx context.Context, key K) (V, error) {
+	res := l.enqueue(key)
+	select {
+	case <-res.done:
+		return res.value, res.err
+	case <-ctx.Done():
+		var zero V
+		return zero, ctx.Err()
+	}
+}
+
+// LoadMany devuelve los valores de las claves que existen, en el orden pedido.
+func (l *Loader[K, V]) LoadMany(ctx context.Context, keys []K) ([]V, error) {
+	results := make([]*result[V], len(keys))
+	for i, key := range keys {
+		results[i] = l.enqueue(key)
+	}
+
+	values := make([]V, 0, len(keys))
+	for _, res := range results {
+		select {
+		case <-res.done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		if errors.Is(res.err, ErrNotFound) {
+			continue
+		}
+		if res.err != nil {
+			return nil, res.err
+		}
+		values = append(values, res.value)
+	}
+	return values, nil
+}
+
+// Prime guarda un valor en la caché, reemplazando el anterior. Se usa después
+// de modificar un documento para que el resto de la petición vea el cambio.
+func (l *Loader[K, V]) Prime(key K, value V) {
+	res := &result[V]{done: make(chan struct{}), value: value}
+	close(res.done)
+
+	l.mu.Lock()
+	l.cache[key] = res
+	l.mu.Unlock()
+}
+
+// Clear quita una clave de la caché.
+func (l *Loader[K, V]) Clear(key K) {
+	l.mu.Lock()
+	delete(l.cache, key)
+	l.mu.Unlock()
+}
+
+// enqueue devuelve el resultado en caché de la clave o la agrega al lote en curso.
+func (l *Loader[K, V]) enqueue(key K) *result[V] {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if res, ok := l.cache[key]; ok {
+		return res
+	}
+
+	res := &result[V]{done: make(chan struct{})}
+	l.cache[key] = res
+
+	if l.pending == nil {
+		l.pending = &batch[K, V]{}
+		pending := l.pending
+		time.AfterFunc(l.wait, func() { l.dispatch(pending) })
+	}
+	l.pending.keys = append(l.pending.keys, key)
+	l.pending.results = append(l.pending.results, res)
+
+	if l.maxBatch > 0 && len(l.pending.keys) >= l.maxBatch {
+		full := l.pending
+		l.pending = nil
+		go l.dispatch(full)
+	}
+	return res
+}
+
+// dispatch consulta las claves de un lote y completa sus resultados.
+func (l *Loader[K, V]) dispatch(b *batch[K, V]) {
+	b.once.Do(func() {
+		l.mu.Lock()
+		if l.pending == b {
+			l.pending = nil
+		}
+		l.mu.Unlock()
+
+		recordBatch(l.name, len(b.keys))
+		values, err := l.fetch(l.ctx, b.keys)
+
+		for i, key := range b.keys {
+			res := b.results[i]
+			value, ok := values[key]
+			switch {
+			case err != nil:
+				res.err = err
+			case !ok:
+				res.err = ErrNotFound
+			default:
+				res.value = value
+			}
+			close(res.done)
+		}
+
+		// Los errores de consulta no se guardan, para que un reintento vuelva a consultar
+		if err != nil {
+			l.mu.Lock()
+			for i, key := range b.keys {
+				if l.cache[key] == b.results[i] {
+					delete(l.cache, key)
+				}
+			}
+			l.mu.Unlock()
+		}
+	})
+}
@@ -0,0 +1,155 @@
+package dataloader
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recorder es una BatchFunc que guarda las claves de cada lote y devuelve las
+// claves pares multiplicadas por diez; las impares no existen.
+type recorder struct {
+	mu      sync.Mutex
+	batches [][]int
+	err     error
+}
+
+func (r *recorder) fetch(ctx context.Context, keys []int) (map[int]int, error) {
+	r.mu.Lock()
+	r.batches = append(r.batches, append([]int(nil), keys...))
+	err := r.err
+	r.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	values := map[int]int{}
+	for _, key := range keys {
+		if key%2 == 0 {
+			values[key] = key * 10
+		}
+	}
+	return values, nil
+}
+
+func (r *recorder) batchSizes() []int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	sizes := make([]int, len(r.batches))
+	for i, batch := range r.batches {
+		sizes[i] = len(batch)
+	}
+	slices.Sort(sizes)
+	return sizes
+}
+
+func TestLoaderBatching(t *testing.T) {
+	tests := []struct {
+		name     string
+		keys     []int
+		maxBatch int
+		want     []int
+	}{
+		{"one key", []int{2}, 0, []int{1}},
+		{"concurrent keys share a batch", []int{2, 4, 6, 8}, 0, []int{4}},
+		{"duplicate keys are fetched once", []int{2, 2, 4, 4}, 0, []int{2}},
+		{"full batches are fetched right away", []int{2, 4, 6, 8, 10}, 2, []int{1, 2, 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &recorder{}
+			loader := New(context.Background(), "test", r.fetch, Options{Wait: 20 * time.Millisecond, MaxBatch: tt.maxBatch})
+
+			var wg sync.WaitGroup
+			for _, key := range tt.keys {
+				wg.Add(1)
+				go func(key int) {
+					defer wg.Done()
+					value, err := loader.Load(context.Background(), key)
+					if err != nil || value != key*10 {
+						t.Errorf("Load(%d) = %d, %v", key, value, err)
+					}
+				}(key)
+			}
+			wg.Wait()
+
+			if got := r.batchSizes(); !slices.Equal(got, tt.want) {
+				t.Errorf("batch sizes = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoaderResults(t *testing.T) {
+	r := &recorder{}
+	loader := New(context.Background(), "test", r.fetch, Options{Wait: time.Millisecond})
+	ctx := context.Background()
+
+	if _, err := loader.Load(ctx, 3); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Load(3) error = %v, want ErrNotFound", err)
+	}
+
+	values, err := loader.LoadMany(ctx, []int{4, 1, 2})
+	if err != nil {
+		t.Fatalf("LoadMany() error = %v", err)
+	}
+	if !slices.Equal(values, []int{40, 20}) {
+		t.Errorf("LoadMany() = %v, want [40 20]", values)
+	}
+
+	// Las claves ya cargadas salen de la caché
+	before := len(r.batchSizes())
+	if value, _ := loader.Load(ctx, 4); value != 40 {
+		t.Errorf("Load(4) = %d, want 40", value)
+	}
+	if len(r.batchSizes()) != before {
+		t.Error("Load of a cached key queried again")
+	}
+
+	loader.Prime(4, 99)
+	if value, _ := loader.Load(ctx, 4); value != 99 {
+		t.Errorf("Load(4) after Prime = %d, want 99", value)
+	}
+
+	loader.Clear(4)
+	if value, _ := loader.Load(ctx, 4); value != 40 {
+		t.Errorf("Load(4) after Clear = %d, want 40", value)
+	}
+}
+
+func TestLoaderDoesNotCacheErrors(t *testing.T) {
+	r := &recorder{err: errors.New("connection refused")}
+	loader := New(context.Background(), "test", r.fetch, Options{Wait: time.Millisecond})
+	ctx := context.Background()
+
+	if _, err := loader.Load(ctx, 2); err == nil || errors.Is(err, ErrNotFound) {
+		t.Fatalf("Load(2) error = %v, want the fetch error", err)
+	}
+
+	r.mu.Lock()
+	r.err = nil
+	r.mu.Unlock()
+	if value, err := loader.Load(ctx, 2); err != nil || value != 20 {
+		t.Errorf("Load(2) after the error = %d, %v, want 20", value, err)
+	}
+}
+
+func TestLoaderCanceledContext(t *testing.T) {
+	block := make(chan struct{})
+	defer close(block)
+	fetch := func(ctx context.Context, keys []int) (map[int]int, error) {
+		<-block
+		return nil, nil
+	}
+	loader := New(context.Background(), "test", fetch, Options{Wait: time.Millisecond})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := loader.Load(ctx, 1); !errors.Is(err, context.Canceled) {
+		t.Errorf("Load() error = %v, want context.Canceled", err)
+	}
+}
@@ -0,0 +1,46 @@
+package dataloader
+
+import (
+	"expvar"
+	"strconv"
+	"sync"
+)
+
+// batchSizeBuckets son los límites superiores del histograma de tamaños de lote.
+var batchSizeBuckets = []int{1, 2, 5, 10, 25, 50, 100}
+
+// metrics se publica como "dataloader" en /debug/vars, en el puerto interno de
+// métricas (METRICS_ADDR): por cada Loader, la cantidad de lotes y de claves
+// consultadas, el lote más grande y un histograma acumulado de tamaños
+// ("le_<n>" cuenta los lotes de hasta n claves).
+var metrics = expvar.NewMap("dataloader")
+
+// metricsMu protege la creación del mapa de cada Loader y el lote más grande.
+var metricsMu sync.Mutex
+
+func recordBatch(name string, size int) {
+	metricsMu.Lock()
+	defer metricsMu.Unlock()
+
+	loader, ok := metrics.Get(name).(*expvar.Map)
+	if !ok {
+		loader = new(expvar.Map)
+		metrics.Set(name, loader)
+	}
+
+	loader.Add("batches", 1)
+	loader.Add("keys", int64(size))
+	for _, bucket := range batchSizeBuckets {
+		if size <= bucket {
+			loader.Add("le_"+strconv.Itoa(bucket), 1)
+		}
+	}
+	loader.Add("le_inf", 1)
+
+	largest, _ := loader.Get("largest").(*expvar.Int)
+	if largest == nil || largest.Value() < int64(size) {
+		value := new(expvar.Int)
+		value.Set(int64(size))
+		loader.Set("largest", value)
+	}
+}
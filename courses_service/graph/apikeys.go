@@ -0,0 +1,23 @@
+package graph
+
+import (
+	"courses_service/apperrors"
+	"time"
+)
+
+// normalizeExpiry valida la fecha de caducidad de una API key y la pasa a UTC,
+// que es como se compara con la hora actual al autenticar.
+func normalizeExpiry(expiresAt *string) (*string, error) {
+	if expiresAt == nil {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, *expiresAt)
+	if err != nil {
+		return nil, apperrors.InvalidArgument("invalid expiresAt %q, expected RFC 3339", *expiresAt)
+	}
+	if !t.After(time.Now()) {
+		return nil, apperrors.InvalidArgument("expiresAt must be in the future")
+	}
+	normalized := t.UTC().Format(time.RFC3339)
+	return &normalized, nil
+}
@@ -0,0 +1,112 @@
+package graph
+
+import (
+	"context"
+	"courses_service/apikeys"
+	"courses_service/apperrors"
+	"courses_service/graph/model"
+	"log"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Resolver para listar las API keys
+func (r *queryResolver) APIKeys(ctx context.Context) ([]*model.APIKey, error) {
+	cursor, err := r.APIKeyCollection.Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "_id", Value: -1}}))
+	if err != nil {
+		log.Printf("Failed to find API keys: %v", err)
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	keys := []*model.APIKey{}
+	if err := cursor.All(ctx, &keys); err != nil {
+		log.Printf("Error decoding API keys: %v", err)
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+// Mutación para crear una API key; la clave solo se devuelve en la respuesta
+func (r *mutationResolver) CreateAPIKey(ctx context.Context, input model.NewAPIKey) (*model.APIKeySecret, error) {
+	if strings.TrimSpace(input.Name) == "" {
+		return nil, apperrors.InvalidArgument("API key name must not be empty")
+	}
+	if len(input.Scopes) == 0 {
+		return nil, apperrors.InvalidArgument("an API key needs at least one scope")
+	}
+	expiresAt, err := normalizeExpiry(input.ExpiresAt)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, hash, err := apikeys.Generate()
+	if err != nil {
+		return nil, err
+	}
+	apiKey := model.APIKey{
+		ID:        primitive.NewObjectID().Hex(),
+		Name:      strings.TrimSpace(input.Name),
+		Hint:      apikeys.Hint(secret),
+		Hash:      hash,
+		Scopes:    input.Scopes,
+		CreatedAt: time.Now().UTC().Format(time.RFC3339),
+		ExpiresAt: expiresAt,
+	}
+
+	if _, err := r.APIKeyCollection.InsertOne(ctx, apiKey); err != nil {
+		log.Printf("Failed to insert new API key: %v", err)
+		return nil, err
+	}
+
+	return &model.APIKeySecret{APIKey: &apiKey, Secret: secret}, nil
+}
+
+// Mutación para reemplazar la clave de una API key conservando su nombre y permisos
+func (r *mutationResolver) RotateAPIKey(ctx context.Context, id string) (*model.APIKeySecret, error) {
+	secret, hash, err := apikeys.Generate()
+	if err != nil {
+		return nil, err
+	}
+
+	apiKey, err := r.updateAPIKey(ctx, id, bson.M{"$set": bson.M{
+		"hash":      hash,
+		"hint":      apikeys.Hint(secret),
+		"rotatedat": time.Now().UTC().Format(time.RFC3339),
+	}})
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.APIKeySecret{APIKey: apiKey, Secret: secret}, nil
+}
+
+// Mutación para revocar una API key; deja de aceptarse de inmediato
+func (r *mutationResolver) RevokeAPIKey(ctx context.Context, id string) (*model.APIKey, error) {
+	return r.updateAPIKey(ctx, id, bson.M{"$set": bson.M{
+		"revokedat": time.Now().UTC().Format(time.RFC3339),
+	}})
+}
+
+// updateAPIKey modifica una API key que no esté revocada.
+func (r *mutationResolver) updateAPIKey(ctx context.Context, id string, update bson.M) (*model.APIKey, error) {
+	var apiKey model.APIKey
+	err := r.APIKeyCollection.FindOneAndUpdate(ctx, bson.M{"_id": id, "revokedat": nil}, update,
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&apiKey)
+	if err == mongo.ErrNoDocuments {
+		return nil, apperrors.NotFound("no active API key found with ID %s", id)
+	}
+	if err != nil {
+		log.Printf("Failed to update API key %s: %v", id, err)
+		return nil, err
+	}
+
+	return &apiKey, nil
+}
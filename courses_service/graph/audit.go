@@ -0,0 +1,47 @@
+package graph
+
+import (
+	"courses_service/apperrors"
+	"courses_service/graph/model"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const maxAuditEntriesPerPage = 200
+
+// auditFilter construye la consulta del registro de auditoría.
+func auditFilter(filter *model.AuditLogFilter) (bson.M, error) {
+	query := bson.M{}
+	if filter == nil {
+		return query, nil
+	}
+
+	if filter.Actor != nil {
+		query["actor"] = *filter.Actor
+	}
+	if filter.Operation != nil {
+		query["operation"] = *filter.Operation
+	}
+	if filter.Success != nil {
+		query["success"] = *filter.Success
+	}
+
+	// Las fechas se guardan en RFC 3339; se normalizan a UTC para compararlas como texto
+	timestamp := bson.M{}
+	for op, value := range map[string]*string{"$gte": filter.From, "$lt": filter.To} {
+		if value == nil {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, *value)
+		if err != nil {
+			return nil, apperrors.InvalidArgument("invalid date %q, expected RFC 3339", *value)
+		}
+		timestamp[op] = t.UTC().Format(time.RFC3339)
+	}
+	if len(timestamp) > 0 {
+		query["timestamp"] = timestamp
+	}
+
+	return query, nil
+}
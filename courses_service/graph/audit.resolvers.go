@@ -0,0 +1,64 @@
+package graph
+
+import (
+	"context"
+	"courses_service/apperrors"
+	"courses_service/graph/model"
+	"log"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Resolver para consultar el registro de auditoría de las mutaciones
+func (r *queryResolver) AuditLog(ctx context.Context, filter *model.AuditLogFilter, first *int, after *string) (*model.AuditLogPage, error) {
+	limit := 50
+	if first != nil {
+		limit = *first
+	}
+	if limit < 1 || limit > maxAuditEntriesPerPage {
+		return nil, apperrors.InvalidArgument("first must be between 1 and %d", maxAuditEntriesPerPage)
+	}
+
+	query, err := auditFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+	total, err := r.AuditCollection.CountDocuments(ctx, query)
+	if err != nil {
+		log.Printf("Failed to count audit entries: %v", err)
+		return nil, err
+	}
+
+	if after != nil {
+		query["_id"] = bson.M{"$lt": *after}
+	}
+
+	findOptions := options.Find().
+		SetSort(bson.D{{Key: "_id", Value: -1}}).
+		SetLimit(int64(limit + 1))
+	cursor, err := r.AuditCollection.Find(ctx, query, findOptions)
+	if err != nil {
+		log.Printf("Failed to find audit entries: %v", err)
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	entries := []*model.AuditEntry{}
+	if err := cursor.All(ctx, &entries); err != nil {
+		log.Printf("Error decoding audit entries: %v", err)
+		return nil, err
+	}
+
+	page := &model.AuditLogPage{TotalCount: int(total)}
+	if len(entries) > limit {
+		entries = entries[:limit]
+		page.HasNextPage = true
+	}
+	if len(entries) > 0 {
+		page.EndCursor = &entries[len(entries)-1].ID
+	}
+	page.Items = entries
+
+	return page, nil
+}
@@ -0,0 +1,58 @@
+package graph
+
+import (
+	"context"
+	"courses_service/apperrors"
+	"courses_service/auth"
+	"courses_service/graph/model"
+
+	"github.com/99designs/gqlgen/graphql"
+)
+
+// requireUser devuelve el usuario autenticado o un error UNAUTHENTICATED.
+func requireUser(ctx context.Context) (*auth.Principal, error) {
+	principal := auth.ForContext(ctx)
+	if principal == nil {
+		return nil, apperrors.Unauthenticated("authentication required")
+	}
+	return principal, nil
+}
+
+// requireOwnerOrAdmin permite la operación solo al usuario userID o a un ADMIN.
+func requireOwnerOrAdmin(ctx context.Context, userID string) (*auth.Principal, error) {
+	principal, err := requireUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if principal.UserID != userID && !hasRole(principal, model.RoleAdmin) {
+		return nil, apperrors.Forbidden("only the owner or an ADMIN can do this")
+	}
+	return principal, nil
+}
+
+// HasRole implementa la directiva @hasRole: el campo solo se resuelve si el
+// usuario autenticado tiene alguno de los roles indicados.
+func HasRole(ctx context.Context, obj interface{}, next graphql.Resolver, roles []model.Role) (interface{}, error) {
+	principal, err := requireUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, role := range roles {
+		if hasRole(principal, role) {
+			return next(ctx)
+		}
+	}
+
+	return nil, apperrors.Forbidden("%s requires one of the roles %v", graphql.GetFieldContext(ctx).Field.Name, roles)
+}
+
+// hasRole indica si el usuario tiene el rol indicado.
+func hasRole(principal *auth.Principal, role model.Role) bool {
+	for _, r := range principal.Roles {
+		if auth.NormalizeRole(r) == string(role) {
+			return true
+		}
+	}
+	return false
+}
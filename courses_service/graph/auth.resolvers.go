@@ -0,0 +1,11 @@
+package graph
+
+import (
+	"context"
+	"courses_service/auth"
+)
+
+// Resolver para el usuario autenticado
+func (r *queryResolver) Me(ctx context.Context) (*auth.Principal, error) {
+	return auth.ForContext(ctx), nil
+}
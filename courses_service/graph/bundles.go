@@ -0,0 +1,93 @@
+package graph
+
+import (
+	"context"
+	"courses_service/apperrors"
+	"courses_service/graph/model"
+	"log"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// findCoursesInOrder busca los cursos y los devuelve en el orden de ids,
+// omitiendo los que ya no existen.
+func (r *Resolver) findCoursesInOrder(ctx context.Context, ids []string) ([]*model.Course, error) {
+	courses, err := r.findCourses(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[string]*model.Course, len(courses))
+	for _, course := range courses {
+		byID[course.ID] = course
+	}
+
+	ordered := make([]*model.Course, 0, len(ids))
+	for _, id := range ids {
+		if course, ok := byID[id]; ok {
+			ordered = append(ordered, course)
+		}
+	}
+	return ordered, nil
+}
+
+// validateCourseList comprueba los datos comunes de paquetes y rutas: título,
+// precio y una lista de cursos existentes sin repetidos.
+func (r *Resolver) validateCourseList(ctx context.Context, title string, price float64, courseIDs []string) error {
+	if strings.TrimSpace(title) == "" {
+		return apperrors.InvalidArgument("title must not be empty")
+	}
+	if price < 0 {
+		return apperrors.InvalidArgument("price must not be negative")
+	}
+	if len(courseIDs) == 0 {
+		return apperrors.InvalidArgument("at least one course is required")
+	}
+
+	seen := make(map[string]bool, len(courseIDs))
+	for _, id := range courseIDs {
+		if seen[id] {
+			return apperrors.InvalidArgument("course %s is listed more than once", id)
+		}
+		seen[id] = true
+	}
+
+	courses, err := r.findCourses(ctx, courseIDs)
+	if err != nil {
+		return err
+	}
+	if len(courses) != len(courseIDs) {
+		return apperrors.InvalidArgument("some courses do not exist")
+	}
+	return nil
+}
+
+// findBundle busca un paquete por ID.
+func (r *Resolver) findBundle(ctx context.Context, id string) (*model.Bundle, error) {
+	var bundle model.Bundle
+	err := r.BundleCollection.FindOne(ctx, bson.M{"_id": id}).Decode(&bundle)
+	if err == mongo.ErrNoDocuments {
+		return nil, apperrors.NotFound("no bundle found with ID %s", id)
+	}
+	if err != nil {
+		log.Printf("Failed to find bundle with ID %s: %v", id, err)
+		return nil, err
+	}
+	return &bundle, nil
+}
+
+// findLearningPath busca una ruta de aprendizaje por ID.
+func (r *Resolver) findLearningPath(ctx context.Context, id string) (*model.LearningPath, error) {
+	var path model.LearningPath
+	err := r.LearningPathCollection.FindOne(ctx, bson.M{"_id": id}).Decode(&path)
+	if err == mongo.ErrNoDocuments {
+		return nil, apperrors.NotFound("no learning path found with ID %s", id)
+	}
+	if err != nil {
+		log.Printf("Failed to find learning path with ID %s: %v", id, err)
+		return nil, err
+	}
+	return &path, nil
+}
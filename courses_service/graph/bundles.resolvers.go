@@ -0,0 +1,286 @@
+package graph
+
+import (
+	"context"
+	"courses_service/apperrors"
+	"courses_service/graph/model"
+	"courses_service/rabbitmq"
+	"log"
+	"math"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Resolver para los cursos de un paquete
+func (r *bundleResolver) Courses(ctx context.Context, obj *model.Bundle) ([]*model.Course, error) {
+	return r.loadCourses(ctx, obj.CourseIDs)
+}
+
+// Resolver para los cursos de una ruta de aprendizaje
+func (r *learningPathResolver) Courses(ctx context.Context, obj *model.LearningPath) ([]*model.Course, error) {
+	return r.loadCourses(ctx, obj.CourseIDs)
+}
+
+// Resolver para el avance del usuario autenticado en una ruta de aprendizaje
+func (r *learningPathResolver) Progress(ctx context.Context, obj *model.LearningPath) (*model.PathProgress, error) {
+	user, err := requireUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	userID := user.UserID
+
+	courses, err := r.loadCourses(ctx, obj.CourseIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	cursor, err := r.EnrollmentCollection.Find(ctx, bson.M{
+		"userid":   userID,
+		"courseid": bson.M{"$in": obj.CourseIDs},
+	})
+	if err != nil {
+		log.Printf("Failed to find enrollments of user %s: %v", userID, err)
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var enrollments []*model.Enrollment
+	if err := cursor.All(ctx, &enrollments); err != nil {
+		log.Printf("Error decoding enrollments: %v", err)
+		return nil, err
+	}
+	byCourse := make(map[string]*model.Enrollment, len(enrollments))
+	for _, e := range enrollments {
+		byCourse[e.CourseID] = e
+	}
+
+	progress := &model.PathProgress{
+		UserID:       userID,
+		TotalCourses: len(courses),
+		Courses:      make([]*model.PathCourseProgress, 0, len(courses)),
+	}
+	total := 0.0
+	for _, course := range courses {
+		enrollment := byCourse[course.ID]
+		if enrollment != nil {
+			total += enrollment.Progress
+			if enrollment.CompletedAt != nil {
+				progress.CompletedCourses++
+			}
+		}
+		progress.Courses = append(progress.Courses, &model.PathCourseProgress{Course: course, Enrollment: enrollment})
+	}
+	if len(courses) > 0 {
+		progress.Progress = math.Round(total/float64(len(courses))*100) / 100
+	}
+
+	return progress, nil
+}
+
+// Mutación para crear un paquete de cursos
+func (r *mutationResolver) CreateBundle(ctx context.Context, input model.NewBundle) (*model.Bundle, error) {
+	if err := r.validateCourseList(ctx, input.Title, input.Price, input.CourseIDs); err != nil {
+		return nil, err
+	}
+
+	bundle := model.Bundle{
+		ID:          primitive.NewObjectID().Hex(),
+		Title:       input.Title,
+		Description: input.Description,
+		Price:       input.Price,
+		CourseIDs:   input.CourseIDs,
+		CreatedAt:   time.Now().Format(time.RFC3339),
+	}
+
+	if _, err := r.BundleCollection.InsertOne(ctx, bundle); err != nil {
+		log.Printf("Failed to insert new bundle: %v", err)
+		return nil, err
+	}
+
+	return &bundle, nil
+}
+
+// Mutación para eliminar un paquete de cursos
+func (r *mutationResolver) DeleteBundle(ctx context.Context, id string) (*string, error) {
+	result, err := r.BundleCollection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		log.Printf("Failed to delete bundle with ID %s: %v", id, err)
+		return nil, err
+	}
+	if result.DeletedCount == 0 {
+		return nil, apperrors.NotFound("no bundle found with ID %s", id)
+	}
+
+	response := "Bundle successfully deleted"
+	return &response, nil
+}
+
+// Mutación para agregar un paquete al carrito con su precio de paquete
+func (r *mutationResolver) AddBundleToCart(ctx context.Context, bundleID string) (string, error) {
+	user, err := requireUser(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	bundle, err := r.findBundle(ctx, bundleID)
+	if err != nil {
+		return "", err
+	}
+
+	courses, err := r.findCoursesInOrder(ctx, bundle.CourseIDs)
+	if err != nil {
+		return "", err
+	}
+
+	err = rabbitmq.SendBundleDetails(bundle, courses, user.UserID)
+	if err != nil {
+		log.Printf("Failed to publish bundle details to RabbitMQ: %v", err)
+		return "", err
+	}
+
+	publishCartEvent(user.UserID, model.CartActionAdded, nil, &bundleID)
+
+	response := "Bundle details sent to user service"
+	return response, nil
+}
+
+// Mutación para crear una ruta de aprendizaje
+func (r *mutationResolver) CreateLearningPath(ctx context.Context, input model.NewLearningPath) (*model.LearningPath, error) {
+	if err := r.validateCourseList(ctx, input.Title, input.Price, input.CourseIDs); err != nil {
+		return nil, err
+	}
+
+	path := model.LearningPath{
+		ID:          primitive.NewObjectID().Hex(),
+		Title:       input.Title,
+		Description: input.Description,
+		Price:       input.Price,
+		CourseIDs:   input.CourseIDs,
+		CreatedAt:   time.Now().Format(time.RFC3339),
+	}
+
+	if _, err := r.LearningPathCollection.InsertOne(ctx, path); err != nil {
+		log.Printf("Failed to insert new learning path: %v", err)
+		return nil, err
+	}
+
+	return &path, nil
+}
+
+// Mutación para eliminar una ruta de aprendizaje
+func (r *mutationResolver) DeleteLearningPath(ctx context.Context, id string) (*string, error) {
+	result, err := r.LearningPathCollection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		log.Printf("Failed to delete learning path with ID %s: %v", id, err)
+		return nil, err
+	}
+	if result.DeletedCount == 0 {
+		return nil, apperrors.NotFound("no learning path found with ID %s", id)
+	}
+
+	response := "Learning path successfully deleted"
+	return &response, nil
+}
+
+// Mutación para inscribir al usuario autenticado en todos los cursos de una ruta. Los
+// prerrequisitos que forman parte de la misma ruta no se exigen.
+func (r *mutationResolver) EnrollInPath(ctx context.Context, pathID string) ([]*model.Enrollment, error) {
+	user, err := requireUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	path, err := r.findLearningPath(ctx, pathID)
+	if err != nil {
+		return nil, err
+	}
+
+	courses, err := r.findCoursesInOrder(ctx, path.CourseIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	inPath := make(map[string]bool, len(path.CourseIDs))
+	for _, id := range path.CourseIDs {
+		inPath[id] = true
+	}
+
+	// Las inscripciones se crean en una transacción: si falla alguna, no queda
+	// inscrito solo en una parte de la ruta
+	var enrollments []*model.Enrollment
+	err = r.withTransaction(ctx, func(ctx mongo.SessionContext) error {
+		enrollments = make([]*model.Enrollment, 0, len(courses))
+		for _, course := range courses {
+			enrollment, err := r.enrollUser(ctx, course, user.UserID, inPath)
+			if err != nil {
+				return err
+			}
+			enrollments = append(enrollments, enrollment)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return enrollments, nil
+}
+
+// Resolver para obtener todos los paquetes
+func (r *queryResolver) Bundles(ctx context.Context) ([]*model.Bundle, error) {
+	cursor, err := r.BundleCollection.Find(ctx, bson.D{})
+	if err != nil {
+		log.Printf("Failed to find bundles: %v", err)
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	bundles := []*model.Bundle{}
+	if err := cursor.All(ctx, &bundles); err != nil {
+		log.Printf("Error decoding bundles: %v", err)
+		return nil, err
+	}
+
+	return bundles, nil
+}
+
+// Resolver para obtener un paquete por ID
+func (r *queryResolver) Bundle(ctx context.Context, id string) (*model.Bundle, error) {
+	// Un paquete que no existe se devuelve como null; solo los fallos reales son errores
+	bundle, err := r.findBundle(ctx, id)
+	if apperrors.CodeOf(err) == apperrors.CodeNotFound {
+		return nil, nil
+	}
+	return bundle, err
+}
+
+// Resolver para obtener todas las rutas de aprendizaje
+func (r *queryResolver) LearningPaths(ctx context.Context) ([]*model.LearningPath, error) {
+	cursor, err := r.LearningPathCollection.Find(ctx, bson.D{})
+	if err != nil {
+		log.Printf("Failed to find learning paths: %v", err)
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	paths := []*model.LearningPath{}
+	if err := cursor.All(ctx, &paths); err != nil {
+		log.Printf("Error decoding learning paths: %v", err)
+		return nil, err
+	}
+
+	return paths, nil
+}
+
+// Resolver para obtener una ruta de aprendizaje por ID
+func (r *queryResolver) LearningPath(ctx context.Context, id string) (*model.LearningPath, error) {
+	// Una ruta que no existe se devuelve como null; solo los fallos reales son errores
+	path, err := r.findLearningPath(ctx, id)
+	if apperrors.CodeOf(err) == apperrors.CodeNotFound {
+		return nil, nil
+	}
+	return path, err
+}
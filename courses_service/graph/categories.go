@@ -0,0 +1,61 @@
+package graph
+
+import (
+	"context"
+	"courses_service/apperrors"
+	"courses_service/categories"
+	"courses_service/graph/model"
+	"log"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// findCategory busca una categoría por ID.
+func (r *Resolver) findCategory(ctx context.Context, id string) (*model.Category, error) {
+	var category model.Category
+	err := r.CategoryCollection.FindOne(ctx, bson.M{"_id": id}).Decode(&category)
+	if err == mongo.ErrNoDocuments {
+		return nil, apperrors.NotFound("no category found with ID %s", id)
+	}
+	if err != nil {
+		log.Printf("Failed to find category with ID %s: %v", id, err)
+		return nil, err
+	}
+	return &category, nil
+}
+
+// checkCategoryParent comprueba que parentID existe y no es la propia categoría
+// ni una de sus subcategorías.
+func (r *Resolver) checkCategoryParent(ctx context.Context, id string, parentID string) error {
+	if _, err := r.findCategory(ctx, parentID); err != nil {
+		return err
+	}
+	if id == "" {
+		return nil
+	}
+
+	all, err := categories.FetchAll(ctx, r.CategoryCollection)
+	if err != nil {
+		return err
+	}
+	for _, descendant := range categories.Descendants(all, id) {
+		if descendant == parentID {
+			return apperrors.InvalidArgument("category %s cannot be moved under its own subcategory %s", id, parentID)
+		}
+	}
+	return nil
+}
+
+// normalizeSlug valida el slug pedido o lo genera a partir del nombre.
+func normalizeSlug(slug *string, name string) (string, error) {
+	source := name
+	if slug != nil {
+		source = *slug
+	}
+	normalized := categories.Slugify(source)
+	if normalized == "" {
+		return "", apperrors.InvalidArgument("category slug must contain letters or digits")
+	}
+	return normalized, nil
+}
@@ -0,0 +1,210 @@
+package graph
+
+import (
+	"context"
+	"courses_service/apperrors"
+	"courses_service/categories"
+	"courses_service/graph/model"
+	"log"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Resolver para la categoría padre
+func (r *categoryResolver) Parent(ctx context.Context, obj *model.Category) (*model.Category, error) {
+	if obj.ParentID == nil {
+		return nil, nil
+	}
+	return r.findCategory(ctx, *obj.ParentID)
+}
+
+// Resolver para las subcategorías directas, ordenadas por posición
+func (r *categoryResolver) Children(ctx context.Context, obj *model.Category) ([]*model.Category, error) {
+	cursor, err := r.CategoryCollection.Find(ctx,
+		bson.M{"parentid": obj.ID},
+		options.Find().SetSort(bson.D{{Key: "position", Value: 1}, {Key: "name", Value: 1}}),
+	)
+	if err != nil {
+		log.Printf("Failed to find children of category %s: %v", obj.ID, err)
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	children := []*model.Category{}
+	if err := cursor.All(ctx, &children); err != nil {
+		log.Printf("Error decoding categories: %v", err)
+		return nil, err
+	}
+
+	return children, nil
+}
+
+// Resolver para la categoría de un curso
+func (r *courseResolver) Category(ctx context.Context, obj *model.Course) (*model.Category, error) {
+	if obj.CategoryID == nil {
+		return nil, nil
+	}
+	return r.findCategory(ctx, *obj.CategoryID)
+}
+
+// Mutación para crear una categoría
+func (r *mutationResolver) CreateCategory(ctx context.Context, input model.NewCategory) (*model.Category, error) {
+	if strings.TrimSpace(input.Name) == "" {
+		return nil, apperrors.InvalidArgument("category name must not be empty")
+	}
+	slug, err := normalizeSlug(input.Slug, input.Name)
+	if err != nil {
+		return nil, err
+	}
+	if input.ParentID != nil {
+		if err := r.checkCategoryParent(ctx, "", *input.ParentID); err != nil {
+			return nil, err
+		}
+	}
+
+	category := model.Category{
+		ID:        primitive.NewObjectID().Hex(),
+		Slug:      slug,
+		Name:      strings.TrimSpace(input.Name),
+		ParentID:  input.ParentID,
+		CreatedAt: time.Now().Format(time.RFC3339),
+	}
+	if input.Position != nil {
+		category.Position = *input.Position
+	}
+
+	_, err = r.CategoryCollection.InsertOne(ctx, category)
+	if mongo.IsDuplicateKeyError(err) {
+		return nil, apperrors.Conflict("a category with slug %s already exists", slug)
+	}
+	if err != nil {
+		log.Printf("Failed to insert new category: %v", err)
+		return nil, err
+	}
+
+	return &category, nil
+}
+
+// Mutación para renombrar, mover o reordenar una categoría
+func (r *mutationResolver) UpdateCategory(ctx context.Context, id string, input model.UpdateCategory) (*model.Category, error) {
+	category, err := r.findCategory(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.Name != nil {
+		if strings.TrimSpace(*input.Name) == "" {
+			return nil, apperrors.InvalidArgument("category name must not be empty")
+		}
+		category.Name = strings.TrimSpace(*input.Name)
+	}
+	if input.Slug != nil {
+		if category.Slug, err = normalizeSlug(input.Slug, category.Name); err != nil {
+			return nil, err
+		}
+	}
+	if input.ClearParent != nil && *input.ClearParent {
+		category.ParentID = nil
+	} else if input.ParentID != nil {
+		if err := r.checkCategoryParent(ctx, id, *input.ParentID); err != nil {
+			return nil, err
+		}
+		category.ParentID = input.ParentID
+	}
+	if input.Position != nil {
+		category.Position = *input.Position
+	}
+
+	_, err = r.CategoryCollection.ReplaceOne(ctx, bson.M{"_id": id}, category)
+	if mongo.IsDuplicateKeyError(err) {
+		return nil, apperrors.Conflict("a category with slug %s already exists", category.Slug)
+	}
+	if err != nil {
+		log.Printf("Failed to update category with ID %s: %v", id, err)
+		return nil, err
+	}
+
+	return category, nil
+}
+
+// Mutación para eliminar una categoría sin subcategorías ni cursos
+func (r *mutationResolver) DeleteCategory(ctx context.Context, id string) (*string, error) {
+	children, err := r.CategoryCollection.CountDocuments(ctx, bson.M{"parentid": id})
+	if err != nil {
+		log.Printf("Failed to count children of category %s: %v", id, err)
+		return nil, err
+	}
+	if children > 0 {
+		return nil, apperrors.Conflict("category %s has subcategories", id)
+	}
+
+	courses, err := r.CourseCollection.CountDocuments(ctx, bson.M{"categoryid": id})
+	if err != nil {
+		log.Printf("Failed to count courses of category %s: %v", id, err)
+		return nil, err
+	}
+	if courses > 0 {
+		return nil, apperrors.Conflict("category %s still has %d courses", id, courses)
+	}
+
+	result, err := r.CategoryCollection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		log.Printf("Failed to delete category with ID %s: %v", id, err)
+		return nil, err
+	}
+	if result.DeletedCount == 0 {
+		return nil, apperrors.NotFound("no category found with ID %s", id)
+	}
+
+	response := "Category successfully deleted"
+	return &response, nil
+}
+
+// Resolver para las categorías raíz
+func (r *queryResolver) Categories(ctx context.Context) ([]*model.Category, error) {
+	all, err := categories.FetchAll(ctx, r.CategoryCollection)
+	if err != nil {
+		log.Printf("Failed to load categories: %v", err)
+		return nil, err
+	}
+
+	roots := []*model.Category{}
+	for _, c := range all {
+		if c.ParentID == nil {
+			roots = append(roots, c)
+		}
+	}
+
+	return roots, nil
+}
+
+// Resolver para obtener una categoría por ID o por slug
+func (r *queryResolver) Category(ctx context.Context, id *string, slug *string) (*model.Category, error) {
+	if id != nil {
+		category, err := r.findCategory(ctx, *id)
+		if apperrors.CodeOf(err) == apperrors.CodeNotFound {
+			return nil, nil
+		}
+		return category, err
+	}
+	if slug == nil {
+		return nil, apperrors.InvalidArgument("either id or slug is required")
+	}
+
+	var category model.Category
+	err := r.CategoryCollection.FindOne(ctx, bson.M{"slug": categories.Slugify(*slug)}).Decode(&category)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		log.Printf("Failed to find category with slug %s: %v", *slug, err)
+		return nil, err
+	}
+
+	return &category, nil
+}
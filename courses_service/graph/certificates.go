@@ -0,0 +1,55 @@
+package graph
+
+import (
+	"context"
+	"courses_service/certificates"
+	"courses_service/graph/model"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// issueCertificate emite y guarda el certificado firmado de una inscripción
+// completada. Si la inscripción ya tiene certificado, lo devuelve: el índice
+// único sobre enrollmentid impide emitir dos.
+func (r *Resolver) issueCertificate(ctx context.Context, enrollment *model.Enrollment) (*model.Certificate, error) {
+	course, err := r.findCourse(ctx, enrollment.CourseID)
+	if err != nil {
+		return nil, err
+	}
+
+	code, err := certificates.NewCode()
+	if err != nil {
+		return nil, err
+	}
+
+	cert := model.Certificate{
+		ID:           primitive.NewObjectID().Hex(),
+		Code:         code,
+		EnrollmentID: enrollment.ID,
+		CourseID:     course.ID,
+		CourseTitle:  course.Title,
+		UserID:       enrollment.UserID,
+		IssuedAt:     time.Now().Format(time.RFC3339),
+	}
+	certificates.Sign(r.CertificateSigner, &cert)
+
+	_, err = r.CertificateCollection.InsertOne(ctx, cert)
+	if mongo.IsDuplicateKeyError(err) {
+		var existing model.Certificate
+		if err := r.CertificateCollection.FindOne(ctx, bson.M{"enrollmentid": enrollment.ID}).Decode(&existing); err != nil {
+			log.Printf("Failed to find certificate of enrollment %s: %v", enrollment.ID, err)
+			return nil, err
+		}
+		return &existing, nil
+	}
+	if err != nil {
+		log.Printf("Failed to insert certificate for enrollment %s: %v", enrollment.ID, err)
+		return nil, err
+	}
+
+	return &cert, nil
+}
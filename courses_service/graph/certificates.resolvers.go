@@ -0,0 +1,87 @@
+package graph
+
+import (
+	"context"
+	"courses_service/apperrors"
+	"courses_service/certificates"
+	"courses_service/graph/model"
+	"log"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Resolver para la ruta de descarga de un certificado
+func (r *certificateResolver) DownloadURL(ctx context.Context, obj *model.Certificate, format *model.CertificateFormat) (string, error) {
+	f := model.CertificateFormatPDF
+	if format != nil {
+		f = *format
+	}
+	return certificates.DownloadURL(obj, f), nil
+}
+
+// Resolver para el certificado de una inscripción completada
+func (r *enrollmentResolver) Certificate(ctx context.Context, obj *model.Enrollment) (*model.Certificate, error) {
+	if obj.CompletedAt == nil {
+		return nil, nil
+	}
+
+	var cert model.Certificate
+	err := r.CertificateCollection.FindOne(ctx, bson.M{"enrollmentid": obj.ID}).Decode(&cert)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		log.Printf("Failed to find certificate of enrollment %s: %v", obj.ID, err)
+		return nil, err
+	}
+
+	return &cert, nil
+}
+
+// Mutación para emitir el certificado de una inscripción completada que no lo
+// tiene, p. ej. porque falló la emisión al completar el curso o porque los
+// certificados se habilitaron después
+func (r *mutationResolver) IssueCertificate(ctx context.Context, enrollmentID string) (*model.Certificate, error) {
+	var enrollment model.Enrollment
+	err := r.EnrollmentCollection.FindOne(ctx, bson.M{"_id": enrollmentID}).Decode(&enrollment)
+	if err == mongo.ErrNoDocuments {
+		return nil, apperrors.NotFound("no enrollment found with ID %s", enrollmentID)
+	}
+	if err != nil {
+		log.Printf("Failed to find enrollment with ID %s: %v", enrollmentID, err)
+		return nil, err
+	}
+	if _, err := requireOwnerOrAdmin(ctx, enrollment.UserID); err != nil {
+		return nil, err
+	}
+
+	if enrollment.CompletedAt == nil {
+		return nil, apperrors.InvalidArgument("enrollment %s is not completed", enrollmentID)
+	}
+	if r.CertificateSigner == nil {
+		return nil, apperrors.Unavailable("certificates are not enabled")
+	}
+
+	return r.issueCertificate(ctx, &enrollment)
+}
+
+// Consulta pública para comprobar la autenticidad de un certificado
+func (r *queryResolver) VerifyCertificate(ctx context.Context, code string) (*model.CertificateVerification, error) {
+	var cert model.Certificate
+	err := r.CertificateCollection.FindOne(ctx, bson.M{"code": certificates.NormalizeCode(code)}).Decode(&cert)
+	if err == mongo.ErrNoDocuments {
+		return &model.CertificateVerification{Valid: false}, nil
+	}
+	if err != nil {
+		log.Printf("Failed to find certificate %s: %v", code, err)
+		return nil, err
+	}
+
+	if r.CertificateSigner == nil || !certificates.Verify(r.CertificateSigner, &cert) {
+		log.Printf("Certificate %s has an invalid signature", cert.Code)
+		return &model.CertificateVerification{Valid: false}, nil
+	}
+
+	return &model.CertificateVerification{Valid: true, Certificate: &cert}, nil
+}
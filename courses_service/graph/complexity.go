@@ -0,0 +1,69 @@
+package graph
+
+import "courses_service/graph/model"
+
+// estimatedListSize es el tamaño que se asume para las listas sin paginación,
+// como los cursos de un paquete o los resultados de courses.
+const estimatedListSize = 20
+
+// NewComplexity devuelve el modelo de costo de las consultas. Cada campo cuesta
+// 1 más el costo de sus subcampos; las listas multiplican el costo de sus
+// elementos por first, o por estimatedListSize si no se paginan.
+func NewComplexity() ComplexityRoot {
+	var c ComplexityRoot
+
+	c.Query.AuditLog = func(childComplexity int, filter *model.AuditLogFilter, first *int, after *string) int {
+		return pageCost(childComplexity, first, 50, maxAuditEntriesPerPage)
+	}
+	c.Course.Reviews = func(childComplexity int, first *int, after *string) int {
+		return pageCost(childComplexity, first, 10, maxReviewsPerPage)
+	}
+
+	c.Query.Courses = func(childComplexity int, filter *model.CourseFilter, sortBy *model.CourseSort, currency *string) int {
+		return listCost(childComplexity)
+	}
+	c.Query.FilterCourses = func(childComplexity int, categoryID *string, minPrice *float64, maxPrice *float64, minRating *float64, tags []string, matchAllTags *bool, sortBy *model.CourseSort, currency *string) int {
+		return listCost(childComplexity)
+	}
+	c.Query.CoursesMissingTranslation = func(childComplexity int, locale string) int {
+		return listCost(childComplexity)
+	}
+	c.Query.CourseRevisions = func(childComplexity int, id string) int {
+		return listCost(childComplexity)
+	}
+	c.Query.MyEnrollments = listCost
+	c.Query.Bundles = listCost
+	c.Query.LearningPaths = listCost
+	c.Query.Categories = listCost
+	c.Query.APIKeys = listCost
+	c.Bundle.Courses = listCost
+	c.LearningPath.Courses = listCost
+	c.PathProgress.Courses = listCost
+	c.Category.Children = listCost
+	c.Course.Prerequisites = listCost
+	c.Course.Lessons = listCost
+	c.Course.Media = listCost
+
+	return c
+}
+
+// listCost es el costo de una lista sin paginación.
+func listCost(childComplexity int) int {
+	return 1 + childComplexity*estimatedListSize
+}
+
+// pageCost es el costo de una página: el de sus elementos multiplicado por
+// first. Los valores fuera de rango se acotan; el resolver los rechaza igual.
+func pageCost(childComplexity int, first *int, defaultFirst int, maxFirst int) int {
+	n := defaultFirst
+	if first != nil {
+		n = *first
+	}
+	if n < 1 {
+		n = 1
+	}
+	if n > maxFirst {
+		n = maxFirst
+	}
+	return 1 + childComplexity*n
+}
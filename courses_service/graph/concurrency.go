@@ -0,0 +1,116 @@
+package graph
+
+import (
+	"context"
+	"courses_service/apperrors"
+	"courses_service/graph/model"
+	"log"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// updateCourse aplica una actualización a un curso, incrementa su versión y
+// registra la revisión resultante en la misma transacción: si no se puede
+// guardar la revisión, el cambio no se aplica. Si version no es nil, la
+// actualización solo se aplica cuando el curso sigue en esa versión; la
+// comprobación forma parte del filtro para que sea atómica.
+func (r *Resolver) updateCourse(ctx context.Context, id string, version *int, update bson.M, action model.RevisionAction) (*model.Course, error) {
+	return r.updateCourseWhere(ctx, id, version, nil, nil, update, action)
+}
+
+// updateCourseWhere es como updateCourse, pero además solo aplica la
+// actualización si el curso cumple condition, que se agrega al filtro. Si el
+// curso existe y está en la versión pedida pero no cumple la condición,
+// devuelve conditionErr.
+func (r *Resolver) updateCourseWhere(ctx context.Context, id string, version *int, condition bson.M, conditionErr error, update bson.M, action model.RevisionAction) (*model.Course, error) {
+	filter := versionFilter(id, version)
+	for key, value := range condition {
+		filter[key] = value
+	}
+
+	var course model.Course
+	err := r.withTransaction(ctx, func(ctx mongo.SessionContext) error {
+		err := r.CourseCollection.FindOneAndUpdate(ctx, filter, incrementVersion(update),
+			options.FindOneAndUpdate().SetReturnDocument(options.After),
+		).Decode(&course)
+		if err != nil {
+			return err
+		}
+		return r.recordRevision(ctx, &course, action)
+	})
+	if err == mongo.ErrNoDocuments {
+		if version == nil && condition == nil {
+			return nil, apperrors.NotFound("no course found with ID %s", id)
+		}
+		// El curso no existe, cambió de versión o no cumple la condición
+		current, err := r.findCourse(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if version != nil && current.Version != *version || condition == nil {
+			return nil, conflictError(current)
+		}
+		return nil, conditionErr
+	}
+	if err != nil {
+		log.Printf("Failed to update course %s: %v", id, err)
+		return nil, err
+	}
+
+	primeCourse(ctx, &course)
+	return &course, nil
+}
+
+// withTransaction ejecuta fn en una transacción de MongoDB, de modo que sus
+// escrituras se aplican todas o ninguna. Requiere un replica set, igual que el
+// change stream de cursos. Si ctx ya pertenece a una transacción, fn se ejecuta
+// dentro de ella.
+func (r *Resolver) withTransaction(ctx context.Context, fn func(ctx mongo.SessionContext) error) error {
+	if session := mongo.SessionFromContext(ctx); session != nil {
+		return fn(mongo.NewSessionContext(ctx, session))
+	}
+
+	session, err := r.DB.Client().StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sc mongo.SessionContext) (interface{}, error) {
+		return nil, fn(sc)
+	})
+	return err
+}
+
+// versionFilter selecciona el curso por ID y, si se indica, por versión. Los
+// cursos anteriores al control de versiones no tienen el campo y están en la 0.
+func versionFilter(id string, version *int) bson.M {
+	filter := bson.M{"_id": id}
+	if version != nil {
+		if *version == 0 {
+			filter["version"] = bson.M{"$in": bson.A{0, nil}}
+		} else {
+			filter["version"] = *version
+		}
+	}
+	return filter
+}
+
+// incrementVersion agrega a la actualización el incremento de la versión del curso.
+func incrementVersion(update bson.M) bson.M {
+	inc, _ := update["$inc"].(bson.M)
+	if inc == nil {
+		inc = bson.M{}
+	}
+	inc["version"] = 1
+	update["$inc"] = inc
+	return update
+}
+
+// conflictError indica que el curso cambió desde que el cliente lo leyó.
+func conflictError(current *model.Course) error {
+	return apperrors.Conflict("course %s was modified by someone else; current version is %d", current.ID, current.Version).
+		With("currentVersion", current.Version)
+}
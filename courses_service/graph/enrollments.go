@@ -0,0 +1,91 @@
+package graph
+
+import (
+	"context"
+	"courses_service/graph/model"
+	"log"
+	"math"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Tipo del evento publicado cuando un usuario completa todas las lecciones de un curso
+const courseCompletedEvent = "course.completed"
+
+// appendLesson agrega una lección al final del curso.
+func appendLesson(lessons []*model.Lesson, title string) []*model.Lesson {
+	return append(lessons, &model.Lesson{
+		ID:       primitive.NewObjectID().Hex(),
+		Title:    title,
+		Position: len(lessons) + 1,
+	})
+}
+
+// hasLesson indica si la lección pertenece al curso.
+func hasLesson(course *model.Course, lessonID string) bool {
+	for _, l := range course.Lessons {
+		if l.ID == lessonID {
+			return true
+		}
+	}
+	return false
+}
+
+// courseProgress calcula el porcentaje de lecciones del curso que están completadas.
+// Las lecciones completadas que ya no existen en el curso no cuentan.
+func courseProgress(course *model.Course, completed []string) float64 {
+	if len(course.Lessons) == 0 {
+		return 0
+	}
+
+	done := make(map[string]bool, len(completed))
+	for _, id := range completed {
+		done[id] = true
+	}
+
+	count := 0
+	for _, l := range course.Lessons {
+		if done[l.ID] {
+			count++
+		}
+	}
+	return math.Round(float64(count)/float64(len(course.Lessons))*10000) / 100
+}
+
+// enrollUser inscribe al usuario en el curso, o devuelve su inscripción si ya
+// existía. Los prerrequisitos incluidos en exempt no se exigen.
+func (r *Resolver) enrollUser(ctx context.Context, course *model.Course, userID string, exempt map[string]bool) (*model.Enrollment, error) {
+	var existing model.Enrollment
+	err := r.EnrollmentCollection.FindOne(ctx, bson.M{"courseid": course.ID, "userid": userID}).Decode(&existing)
+	if err == nil {
+		return &existing, nil
+	}
+	if err != mongo.ErrNoDocuments {
+		log.Printf("Failed to find enrollment: %v", err)
+		return nil, err
+	}
+
+	if err := r.checkPrerequisitesCompleted(ctx, course, userID, exempt); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().Format(time.RFC3339)
+	enrollment := model.Enrollment{
+		ID:               primitive.NewObjectID().Hex(),
+		CourseID:         course.ID,
+		UserID:           userID,
+		CompletedLessons: []string{},
+		EnrolledAt:       now,
+		LastAccessedAt:   now,
+	}
+
+	if _, err := r.EnrollmentCollection.InsertOne(ctx, enrollment); err != nil {
+		log.Printf("Failed to insert enrollment: %v", err)
+		return nil, err
+	}
+
+	return &enrollment, nil
+}
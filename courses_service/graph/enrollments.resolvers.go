@@ -0,0 +1,168 @@
+package graph
+
+import (
+	"context"
+	"courses_service/apperrors"
+	"courses_service/graph/model"
+	"courses_service/rabbitmq"
+	"log"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Resolver para el curso de una inscripción
+func (r *enrollmentResolver) Course(ctx context.Context, obj *model.Enrollment) (*model.Course, error) {
+	return r.loadCourse(ctx, obj.CourseID)
+}
+
+// Mutación para agregar una lección al final de un curso
+func (r *mutationResolver) AddLesson(ctx context.Context, courseID string, input model.NewLesson, version int) (*model.Course, error) {
+	if strings.TrimSpace(input.Title) == "" {
+		return nil, apperrors.InvalidArgument("lesson title must not be empty")
+	}
+
+	course, err := r.findCourse(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+	if course.Version != version {
+		return nil, conflictError(course)
+	}
+
+	// El ID de la lección se calcula con las lecciones leídas; el filtro por
+	// versión garantiza que nadie agregó otra mientras tanto
+	lessons := appendLesson(course.Lessons, input.Title)
+	return r.updateCourse(ctx, courseID, &version, bson.M{"$push": bson.M{"lessons": lessons[len(lessons)-1]}}, model.RevisionActionUpdate)
+}
+
+// Mutación para inscribir al usuario autenticado en un curso; si ya estaba inscrito devuelve su inscripción
+func (r *mutationResolver) Enroll(ctx context.Context, courseID string) (*model.Enrollment, error) {
+	user, err := requireUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	course, err := r.findCourse(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.enrollUser(ctx, course, user.UserID, nil)
+}
+
+// Mutación para marcar una lección como completada y actualizar el avance
+func (r *mutationResolver) MarkLessonComplete(ctx context.Context, courseID string, lessonID string) (*model.Enrollment, error) {
+	user, err := requireUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	userID := user.UserID
+
+	course, err := r.findCourse(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+	if !hasLesson(course, lessonID) {
+		return nil, apperrors.InvalidArgument("lesson %s does not belong to course %s", lessonID, courseID)
+	}
+
+	// $addToSet evita duplicados aunque la misma lección se marque dos veces a la vez
+	var enrollment model.Enrollment
+	err = r.EnrollmentCollection.FindOneAndUpdate(ctx,
+		bson.M{"courseid": courseID, "userid": userID},
+		bson.M{
+			"$addToSet": bson.M{"completedlessons": lessonID},
+			"$set": bson.M{
+				"lastlessonid":   lessonID,
+				"lastaccessedat": time.Now().Format(time.RFC3339),
+			},
+		},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&enrollment)
+	if err != nil {
+		log.Printf("Failed to update enrollment of user %s in course %s: %v", userID, courseID, err)
+		return nil, err
+	}
+
+	// $max evita que una finalización concurrente que leyó menos lecciones
+	// sobrescriba un progreso mayor
+	err = r.EnrollmentCollection.FindOneAndUpdate(ctx,
+		bson.M{"_id": enrollment.ID},
+		bson.M{"$max": bson.M{"progress": courseProgress(course, enrollment.CompletedLessons)}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&enrollment)
+	if err != nil {
+		log.Printf("Failed to update progress of enrollment %s: %v", enrollment.ID, err)
+		return nil, err
+	}
+
+	if enrollment.Progress >= 100 && enrollment.CompletedAt == nil {
+		if err := r.completeEnrollment(ctx, &enrollment); err != nil {
+			return nil, err
+		}
+	}
+
+	return &enrollment, nil
+}
+
+// Resolver para obtener las inscripciones del usuario autenticado
+func (r *queryResolver) MyEnrollments(ctx context.Context) ([]*model.Enrollment, error) {
+	user, err := requireUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	userID := user.UserID
+
+	cursor, err := r.EnrollmentCollection.Find(ctx,
+		bson.M{"userid": userID},
+		options.Find().SetSort(bson.D{{Key: "lastaccessedat", Value: -1}}),
+	)
+	if err != nil {
+		log.Printf("Failed to find enrollments of user %s: %v", userID, err)
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	enrollments := []*model.Enrollment{}
+	if err := cursor.All(ctx, &enrollments); err != nil {
+		log.Printf("Error decoding enrollments: %v", err)
+		return nil, err
+	}
+
+	return enrollments, nil
+}
+
+// completeEnrollment registra la fecha de finalización, publica course.completed y
+// emite el certificado. El filtro sobre completedat garantiza que ambos ocurran una sola vez.
+func (r *mutationResolver) completeEnrollment(ctx context.Context, enrollment *model.Enrollment) error {
+	completedAt := time.Now().Format(time.RFC3339)
+	result, err := r.EnrollmentCollection.UpdateOne(ctx,
+		bson.M{"_id": enrollment.ID, "completedat": nil},
+		bson.M{"$set": bson.M{"completedat": completedAt}},
+	)
+	if err != nil {
+		log.Printf("Failed to complete enrollment %s: %v", enrollment.ID, err)
+		return err
+	}
+	if result.ModifiedCount == 0 {
+		return nil
+	}
+
+	enrollment.CompletedAt = &completedAt
+	err = rabbitmq.PublishEvent(courseCompletedEvent, enrollment)
+	if err != nil {
+		log.Printf("Failed to publish %s event to RabbitMQ: %v", courseCompletedEvent, err)
+	}
+
+	// Un fallo al emitir el certificado no revierte la finalización del curso; el
+	// certificado se puede emitir después con la mutación issueCertificate
+	if r.CertificateSigner != nil {
+		if _, err := r.issueCertificate(ctx, enrollment); err != nil {
+			log.Printf("Failed to issue certificate for enrollment %s: %v", enrollment.ID, err)
+		}
+	}
+	return nil
+}
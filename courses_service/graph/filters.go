@@ -0,0 +1,78 @@
+package graph
+
+import (
+	"context"
+	"courses_service/categories"
+	"courses_service/graph/model"
+	"log"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// courseFilter traduce los criterios de búsqueda de cursos a un filtro de MongoDB.
+func (r *Resolver) courseFilter(ctx context.Context, f *model.CourseFilter) (bson.D, error) {
+	filter := bson.D{}
+	if f == nil {
+		return filter, nil
+	}
+
+	if f.CategoryID != nil {
+		all, err := categories.FetchAll(ctx, r.CategoryCollection)
+		if err != nil {
+			log.Printf("Failed to load categories: %v", err)
+			return nil, err
+		}
+		ids := categories.Descendants(all, *f.CategoryID)
+		filter = append(filter, bson.E{Key: "categoryid", Value: bson.D{{Key: "$in", Value: ids}}})
+	}
+	if f.MinPrice != nil && f.MaxPrice != nil {
+		filter = append(filter, bson.E{Key: "price", Value: bson.D{
+			{Key: "$gte", Value: *f.MinPrice},
+			{Key: "$lte", Value: *f.MaxPrice},
+		}})
+	}
+	if f.MinRating != nil {
+		filter = append(filter, bson.E{Key: "averagerating", Value: bson.D{{Key: "$gte", Value: *f.MinRating}}})
+	}
+	if len(f.Tags) > 0 {
+		tags, err := normalizeTags(f.Tags)
+		if err != nil {
+			return nil, err
+		}
+		operator := "$in"
+		if f.MatchAllTags != nil && *f.MatchAllTags {
+			operator = "$all"
+		}
+		filter = append(filter, bson.E{Key: "tags", Value: bson.D{{Key: operator, Value: tags}}})
+	}
+
+	if f.Level != nil {
+		filter = append(filter, bson.E{Key: "level", Value: *f.Level})
+	}
+	if f.Language != nil {
+		lang, err := normalizeLanguage(*f.Language)
+		if err != nil {
+			return nil, err
+		}
+		filter = append(filter, bson.E{Key: "language", Value: lang})
+	}
+	if f.SubtitleLanguage != nil {
+		lang, err := normalizeLanguage(*f.SubtitleLanguage)
+		if err != nil {
+			return nil, err
+		}
+		filter = append(filter, bson.E{Key: "subtitlelanguages", Value: lang})
+	}
+	if f.MinHours != nil || f.MaxHours != nil {
+		hours := bson.D{}
+		if f.MinHours != nil {
+			hours = append(hours, bson.E{Key: "$gte", Value: *f.MinHours})
+		}
+		if f.MaxHours != nil {
+			hours = append(hours, bson.E{Key: "$lte", Value: *f.MaxHours})
+		}
+		filter = append(filter, bson.E{Key: "estimatedhours", Value: hours})
+	}
+
+	return filter, nil
+}
@@ -0,0 +1,93 @@
+package graph
+
+import (
+	"context"
+	"courses_service/apperrors"
+	"courses_service/dataloader"
+	"courses_service/graph/model"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+)
+
+// Loaders agrupa los dataloaders de una petición.
+type Loaders struct {
+	Courses *dataloader.Loader[string, *model.Course]
+}
+
+type loadersKey struct{}
+
+// LoaderMiddleware crea dataloaders nuevos en cada petición HTTP, de modo que
+// los resolvers anidados que buscan cursos comparten una sola consulta $in y
+// nunca ven datos de otra petición. Las conexiones websocket de las
+// suscripciones no los usan: viven demasiado como para guardar una caché.
+func (r *Resolver) LoaderMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if strings.EqualFold(req.Header.Get("Upgrade"), "websocket") {
+			next.ServeHTTP(w, req)
+			return
+		}
+		ctx := req.Context()
+		loaders := &Loaders{
+			Courses: dataloader.New(ctx, "courses", r.fetchCourses, dataloader.DefaultOptions),
+		}
+		next.ServeHTTP(w, req.WithContext(context.WithValue(ctx, loadersKey{}, loaders)))
+	})
+}
+
+func loadersFor(ctx context.Context) *Loaders {
+	loaders, _ := ctx.Value(loadersKey{}).(*Loaders)
+	return loaders
+}
+
+// fetchCourses es la función de carga del dataloader de cursos.
+func (r *Resolver) fetchCourses(ctx context.Context, ids []string) (map[string]*model.Course, error) {
+	courses, err := r.findCourses(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]*model.Course, len(courses))
+	for _, course := range courses {
+		byID[course.ID] = course
+	}
+	return byID, nil
+}
+
+// loadCourse busca un curso por ID con el dataloader de la petición, o
+// directamente si no hay uno. Como findCourse, devuelve un error NOT_FOUND si
+// el curso no existe.
+func (r *Resolver) loadCourse(ctx context.Context, id string) (*model.Course, error) {
+	loaders := loadersFor(ctx)
+	if loaders == nil {
+		return r.findCourse(ctx, id)
+	}
+
+	course, err := loaders.Courses.Load(ctx, id)
+	if errors.Is(err, dataloader.ErrNotFound) {
+		return nil, apperrors.NotFound("no course found with ID %s", id)
+	}
+	if err != nil {
+		log.Printf("Failed to find course with ID %s: %v", id, err)
+		return nil, err
+	}
+	return course, nil
+}
+
+// loadCourses busca varios cursos con el dataloader de la petición y los
+// devuelve en el orden de ids, omitiendo los que ya no existen.
+func (r *Resolver) loadCourses(ctx context.Context, ids []string) ([]*model.Course, error) {
+	loaders := loadersFor(ctx)
+	if loaders == nil {
+		return r.findCoursesInOrder(ctx, ids)
+	}
+	return loaders.Courses.LoadMany(ctx, ids)
+}
+
+// primeCourse reemplaza el curso guardado en el dataloader de la petición
+// después de modificarlo.
+func primeCourse(ctx context.Context, course *model.Course) {
+	if loaders := loadersFor(ctx); loaders != nil {
+		loaders.Courses.Prime(course.ID, course)
+	}
+}
@@ -0,0 +1,81 @@
+package graph
+
+import (
+	"context"
+	"courses_service/apperrors"
+	"courses_service/dataloader"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+const courseID = "66f1c0d2a4b5c6d7e8f90123"
+
+// withLoaders devuelve un contexto con dataloaders, como el de LoaderMiddleware.
+func withLoaders(ctx context.Context, r *Resolver) context.Context {
+	loaders := &Loaders{
+		Courses: dataloader.New(ctx, "courses", r.fetchCourses, dataloader.DefaultOptions),
+	}
+	return context.WithValue(ctx, loadersKey{}, loaders)
+}
+
+func TestCourseQuery(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	contexts := map[string]func(*Resolver) context.Context{
+		"without loaders": func(r *Resolver) context.Context { return context.Background() },
+		"with loaders":    func(r *Resolver) context.Context { return withLoaders(context.Background(), r) },
+	}
+
+	for name, newContext := range contexts {
+		mt.Run(name+"/missing course is null", func(mt *mtest.T) {
+			mt.AddMockResponses(mtest.CreateCursorResponse(0, "coursesDB.courses", mtest.FirstBatch))
+			r := &Resolver{CourseCollection: mt.Coll}
+
+			course, err := (&queryResolver{r}).Course(newContext(r), courseID, nil)
+			if err != nil {
+				mt.Fatalf("Course() error = %v", err)
+			}
+			if course != nil {
+				mt.Errorf("Course() = %+v, want nil", course)
+			}
+		})
+
+		mt.Run(name+"/existing course", func(mt *mtest.T) {
+			mt.AddMockResponses(mtest.CreateCursorResponse(0, "coursesDB.courses", mtest.FirstBatch, bson.D{
+				{Key: "_id", Value: courseID},
+				{Key: "title", Value: "Go"},
+				{Key: "price", Value: 10.0},
+			}))
+			r := &Resolver{CourseCollection: mt.Coll}
+
+			course, err := (&queryResolver{r}).Course(newContext(r), courseID, nil)
+			if err != nil {
+				mt.Fatalf("Course() error = %v", err)
+			}
+			if course == nil || course.ID != courseID || course.Title != "Go" {
+				mt.Errorf("Course() = %+v, want course %s", course, courseID)
+			}
+		})
+	}
+
+	mt.Run("invalid id", func(mt *mtest.T) {
+		r := &Resolver{CourseCollection: mt.Coll}
+
+		_, err := (&queryResolver{r}).Course(context.Background(), "not-an-id", nil)
+		if code := apperrors.CodeOf(err); code != apperrors.CodeInvalidArgument {
+			mt.Errorf("Course() error = %v, want code %s", err, apperrors.CodeInvalidArgument)
+		}
+	})
+
+	mt.Run("database error", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateCommandErrorResponse(mtest.CommandError{Code: 2, Message: "bad value"}))
+		r := &Resolver{CourseCollection: mt.Coll}
+
+		course, err := (&queryResolver{r}).Course(context.Background(), courseID, nil)
+		if err == nil || course != nil {
+			mt.Errorf("Course() = %+v, %v, want an error", course, err)
+		}
+	})
+}
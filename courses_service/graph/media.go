@@ -0,0 +1,141 @@
+package graph
+
+import (
+	"bytes"
+	"courses_service/apperrors"
+	"courses_service/graph/model"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/99designs/gqlgen/graphql"
+)
+
+const (
+	maxImageUploadBytes = 5 << 20
+	maxVideoUploadBytes = 200 << 20
+)
+
+// mediaTypes son los tipos MIME aceptados, con su tipo de archivo y extensión.
+var mediaTypes = map[string]struct {
+	kind model.MediaKind
+	ext  string
+}{
+	"image/jpeg": {model.MediaKindImage, ".jpg"},
+	"image/png":  {model.MediaKindImage, ".png"},
+	"image/webp": {model.MediaKindImage, ".webp"},
+	"image/gif":  {model.MediaKindImage, ".gif"},
+	"video/mp4":  {model.MediaKindVideo, ".mp4"},
+	"video/webm": {model.MediaKindVideo, ".webm"},
+}
+
+// sniffedUpload es un archivo subido cuyo tipo se dedujo del contenido.
+type sniffedUpload struct {
+	contentType string
+	kind        model.MediaKind
+	ext         string
+	reader      *exactSizeReader
+}
+
+// exactSizeReader lee un archivo subido y falla si su contenido no tiene
+// exactamente el tamaño declarado, en vez de truncarlo o guardarlo incompleto.
+type exactSizeReader struct {
+	r         io.Reader
+	remaining int64
+	// mismatch indica que el tamaño real no coincidió con el declarado.
+	mismatch bool
+}
+
+var errUploadSize = errors.New("uploaded file size does not match the declared size")
+
+func (e *exactSizeReader) Read(p []byte) (int, error) {
+	n, err := e.r.Read(p)
+	e.remaining -= int64(n)
+	if e.remaining < 0 || (err == io.EOF && e.remaining > 0) {
+		e.mismatch = true
+		return n, errUploadSize
+	}
+	return n, err
+}
+
+// sniffUpload detecta el tipo MIME a partir de los primeros bytes del archivo,
+// sin confiar en el tipo que declara el cliente, y valida el tamaño según el tipo.
+func sniffUpload(file graphql.Upload) (*sniffedUpload, error) {
+	head := make([]byte, 512)
+	n, err := io.ReadFull(file.File, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return nil, apperrors.InvalidArgument("could not read the uploaded file").Wrap(err)
+	}
+	head = head[:n]
+
+	contentType := http.DetectContentType(head)
+	mediaType, ok := mediaTypes[contentType]
+	if !ok {
+		return nil, apperrors.InvalidArgument("unsupported media type %s", contentType)
+	}
+
+	limit := int64(maxImageUploadBytes)
+	if mediaType.kind == model.MediaKindVideo {
+		limit = maxVideoUploadBytes
+	}
+	if file.Size > limit {
+		return nil, apperrors.InvalidArgument("%s files must be at most %d MB", contentType, limit>>20)
+	}
+
+	return &sniffedUpload{
+		contentType: contentType,
+		kind:        mediaType.kind,
+		ext:         mediaType.ext,
+		// El tamaño declarado podría no coincidir con el contenido real
+		reader: &exactSizeReader{r: io.MultiReader(bytes.NewReader(head), file.File), remaining: file.Size},
+	}, nil
+}
+
+// thumbnailMedia devuelve la imagen elegida como miniatura o, si no hay, la primera imagen.
+func thumbnailMedia(course *model.Course) *model.CourseMedia {
+	var first *model.CourseMedia
+	for _, m := range course.Media {
+		if m.Kind != model.MediaKindImage {
+			continue
+		}
+		if course.ThumbnailMediaID != nil && m.ID == *course.ThumbnailMediaID {
+			return m
+		}
+		if first == nil {
+			first = m
+		}
+	}
+	return first
+}
+
+// closestVariant elige la variante más pequeña que alcanza el ancho pedido o, si
+// ninguna lo alcanza, la más grande. Si se indica un formato y hay variantes en
+// ese formato, solo se consideran esas.
+func closestVariant(variants []*model.ImageVariant, width int, format *model.ImageFormat) *model.ImageVariant {
+	candidates := variants
+	if format != nil {
+		var matching []*model.ImageVariant
+		for _, v := range variants {
+			if v.Format == *format {
+				matching = append(matching, v)
+			}
+		}
+		if len(matching) > 0 {
+			candidates = matching
+		}
+	}
+
+	var best, largest *model.ImageVariant
+	for _, v := range candidates {
+		if v.Width >= width && (best == nil || v.Width < best.Width) {
+			best = v
+		}
+		if largest == nil || v.Width > largest.Width {
+			largest = v
+		}
+	}
+	if best != nil {
+		return best
+	}
+	return largest
+}
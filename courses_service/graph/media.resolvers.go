@@ -0,0 +1,157 @@
+package graph
+
+import (
+	"context"
+	"courses_service/apperrors"
+	"courses_service/graph/model"
+	"courses_service/rabbitmq"
+	"log"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Resolver para la URL de la miniatura de un curso
+func (r *courseResolver) ThumbnailURL(ctx context.Context, obj *model.Course) (*string, error) {
+	media := thumbnailMedia(obj)
+	if media == nil {
+		return nil, nil
+	}
+	url := r.BlobStore.URL(media.Key)
+	return &url, nil
+}
+
+// Resolver para la variante de la miniatura más cercana al ancho pedido
+func (r *courseResolver) Thumbnail(ctx context.Context, obj *model.Course, width int, format *model.ImageFormat) (*model.ImageVariant, error) {
+	if width <= 0 {
+		return nil, apperrors.InvalidArgument("width must be positive")
+	}
+	media := thumbnailMedia(obj)
+	if media == nil {
+		return nil, nil
+	}
+	return closestVariant(media.Variants, width, format), nil
+}
+
+// Resolver para la URL pública de un archivo
+func (r *courseMediaResolver) URL(ctx context.Context, obj *model.CourseMedia) (string, error) {
+	return r.BlobStore.URL(obj.Key), nil
+}
+
+// Resolver para la URL pública de una variante de imagen
+func (r *imageVariantResolver) URL(ctx context.Context, obj *model.ImageVariant) (string, error) {
+	return r.BlobStore.URL(obj.Key), nil
+}
+
+// Mutación para subir una imagen o un video promocional a un curso
+func (r *mutationResolver) UploadCourseMedia(ctx context.Context, courseID string, file graphql.Upload, setAsThumbnail *bool, version int) (*model.CourseMediaUpload, error) {
+	course, err := r.findCourse(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+	// Se comprueba antes de guardar el archivo para no subirlo en vano
+	if course.Version != version {
+		return nil, conflictError(course)
+	}
+
+	upload, err := sniffUpload(file)
+	if err != nil {
+		return nil, err
+	}
+	thumbnail := setAsThumbnail != nil && *setAsThumbnail
+	if thumbnail && upload.kind != model.MediaKindImage {
+		return nil, apperrors.InvalidArgument("only images can be used as thumbnail")
+	}
+
+	media := model.CourseMedia{
+		ID:          primitive.NewObjectID().Hex(),
+		Kind:        upload.kind,
+		ContentType: upload.contentType,
+		Size:        int(file.Size),
+		Filename:    file.Filename,
+		UploadedAt:  time.Now().Format(time.RFC3339),
+		Variants:    []*model.ImageVariant{},
+	}
+	media.Key = "courses/" + courseID + "/" + media.ID + upload.ext
+	if media.Kind == model.MediaKindImage {
+		status := model.MediaProcessingStatusPending
+		media.ProcessingStatus = &status
+	}
+
+	if err := r.BlobStore.Put(ctx, media.Key, upload.reader, file.Size, upload.contentType); err != nil {
+		if upload.reader.mismatch {
+			return nil, apperrors.InvalidArgument("the uploaded file does not have the declared size of %d bytes", file.Size)
+		}
+		log.Printf("Failed to store media for course %s: %v", courseID, err)
+		return nil, err
+	}
+
+	update := bson.M{"$push": bson.M{"media": media}}
+	if thumbnail {
+		update["$set"] = bson.M{"thumbnailmediaid": media.ID}
+	}
+	updated, err := r.updateCourse(ctx, courseID, &version, update, model.RevisionActionUpdate)
+	if err != nil {
+		log.Printf("Failed to attach media to course %s: %v", courseID, err)
+		// El archivo ya no está referenciado por ningún curso
+		if err := r.BlobStore.Delete(context.Background(), media.Key); err != nil {
+			log.Printf("Failed to delete orphan media %s: %v", media.Key, err)
+		}
+		return nil, err
+	}
+
+	// Las variantes se generan en segundo plano para que la subida responda rápido
+	if media.Kind == model.MediaKindImage {
+		job := rabbitmq.ImageJob{CourseID: courseID, MediaID: media.ID, Key: media.Key}
+		if err := rabbitmq.PublishImageJob(job); err != nil {
+			log.Printf("Failed to queue image job for media %s: %v", media.ID, err)
+		}
+	}
+
+	return &model.CourseMediaUpload{Media: &media, Course: updated}, nil
+}
+
+// Mutación para eliminar un archivo de un curso
+func (r *mutationResolver) DeleteCourseMedia(ctx context.Context, courseID string, mediaID string, version int) (*model.Course, error) {
+	course, err := r.findCourse(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+	if course.Version != version {
+		return nil, conflictError(course)
+	}
+
+	var media *model.CourseMedia
+	for _, m := range course.Media {
+		if m.ID == mediaID {
+			media = m
+		}
+	}
+	if media == nil {
+		return nil, apperrors.NotFound("no media found with ID %s in course %s", mediaID, courseID)
+	}
+
+	update := bson.M{"$pull": bson.M{"media": bson.M{"id": mediaID}}}
+	if course.ThumbnailMediaID != nil && *course.ThumbnailMediaID == mediaID {
+		update["$unset"] = bson.M{"thumbnailmediaid": ""}
+	}
+
+	updated, err := r.updateCourse(ctx, courseID, &version, update, model.RevisionActionUpdate)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := []string{media.Key}
+	for _, v := range media.Variants {
+		keys = append(keys, v.Key)
+	}
+	for _, key := range keys {
+		if err := r.BlobStore.Delete(ctx, key); err != nil {
+			log.Printf("Failed to delete media %s: %v", key, err)
+		}
+	}
+
+	return updated, nil
+}
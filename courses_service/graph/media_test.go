@@ -0,0 +1,122 @@
+package graph
+
+import (
+	"bytes"
+	"courses_service/apperrors"
+	"courses_service/graph/model"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/99designs/gqlgen/graphql"
+)
+
+// pngHeader son los primeros bytes de un archivo PNG.
+var pngHeader = []byte("\x89PNG\r\n\x1a\n")
+
+func TestExactSizeReader(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		size    int64
+		wantErr bool
+	}{
+		{name: "exact size", content: "abcdef", size: 6},
+		{name: "empty", content: "", size: 0},
+		{name: "shorter than declared", content: "abc", size: 6, wantErr: true},
+		{name: "longer than declared", content: "abcdefgh", size: 6, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader := &exactSizeReader{r: strings.NewReader(tt.content), remaining: tt.size}
+			data, err := io.ReadAll(reader)
+			if tt.wantErr {
+				if !errors.Is(err, errUploadSize) || !reader.mismatch {
+					t.Errorf("ReadAll() error = %v, mismatch = %v, want errUploadSize", err, reader.mismatch)
+				}
+				return
+			}
+			if err != nil || reader.mismatch {
+				t.Fatalf("ReadAll() error = %v, mismatch = %v", err, reader.mismatch)
+			}
+			if string(data) != tt.content {
+				t.Errorf("ReadAll() = %q, want %q", data, tt.content)
+			}
+		})
+	}
+}
+
+func TestSniffUpload(t *testing.T) {
+	png := append(append([]byte{}, pngHeader...), bytes.Repeat([]byte{0}, 1000)...)
+
+	tests := []struct {
+		name     string
+		content  []byte
+		size     int64
+		wantKind model.MediaKind
+		wantCode apperrors.Code
+	}{
+		{
+			name:     "image detected from content",
+			content:  png,
+			size:     int64(len(png)),
+			wantKind: model.MediaKindImage,
+		},
+		{
+			name:     "unsupported type",
+			content:  []byte("<html><body>hi</body></html>"),
+			size:     28,
+			wantCode: apperrors.CodeInvalidArgument,
+		},
+		{
+			name:     "image larger than the limit",
+			content:  png,
+			size:     maxImageUploadBytes + 1,
+			wantCode: apperrors.CodeInvalidArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			upload := graphql.Upload{
+				File:        bytes.NewReader(tt.content),
+				Filename:    "file.png",
+				Size:        tt.size,
+				ContentType: "image/png",
+			}
+			got, err := sniffUpload(upload)
+			if tt.wantCode != "" {
+				if code := apperrors.CodeOf(err); code != tt.wantCode {
+					t.Errorf("sniffUpload() error = %v, want code %s", err, tt.wantCode)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("sniffUpload() error = %v", err)
+			}
+			if got.kind != tt.wantKind || got.contentType != "image/png" || got.ext != ".png" {
+				t.Errorf("sniffUpload() = %+v", got)
+			}
+
+			// El lector devuelve el archivo completo, incluidos los bytes leídos para detectar el tipo
+			data, err := io.ReadAll(got.reader)
+			if err != nil || !bytes.Equal(data, tt.content) {
+				t.Errorf("ReadAll() = %d bytes, %v, want %d bytes", len(data), err, len(tt.content))
+			}
+		})
+	}
+}
+
+func TestSniffUploadDeclaredSizeMismatch(t *testing.T) {
+	png := append(append([]byte{}, pngHeader...), bytes.Repeat([]byte{0}, 1000)...)
+
+	got, err := sniffUpload(graphql.Upload{File: bytes.NewReader(png), Size: 100})
+	if err != nil {
+		t.Fatalf("sniffUpload() error = %v", err)
+	}
+	if _, err := io.ReadAll(got.reader); !errors.Is(err, errUploadSize) {
+		t.Errorf("ReadAll() error = %v, want errUploadSize", err)
+	}
+}
@@ -0,0 +1,148 @@
+package graph
+
+import (
+	"courses_service/apperrors"
+	"courses_service/graph/model"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"golang.org/x/text/language"
+)
+
+const (
+	maxEstimatedHours      = 1000
+	maxLearningOutcomes    = 20
+	maxLearningOutcomeSize = 200
+)
+
+// normalizeLanguage valida un código de idioma ISO 639 y lo devuelve en su forma
+// canónica de dos letras cuando existe (p. ej. "spa" -> "es").
+func normalizeLanguage(code string) (string, error) {
+	base, err := language.ParseBase(strings.TrimSpace(code))
+	if err != nil {
+		return "", apperrors.InvalidArgument("invalid language code %q", code)
+	}
+	return base.String(), nil
+}
+
+// normalizeLanguages valida una lista de códigos de idioma y elimina repetidos.
+func normalizeLanguages(codes []string) ([]string, error) {
+	seen := map[string]bool{}
+	result := make([]string, 0, len(codes))
+	for _, code := range codes {
+		normalized, err := normalizeLanguage(code)
+		if err != nil {
+			return nil, err
+		}
+		if !seen[normalized] {
+			seen[normalized] = true
+			result = append(result, normalized)
+		}
+	}
+	return result, nil
+}
+
+// applyCourseMetadata valida el nivel, idiomas, duración y objetivos de
+// aprendizaje de un curso nuevo y los copia en el curso.
+func applyCourseMetadata(course *model.Course, input model.NewCourse) error {
+	course.Level = input.Level
+
+	if input.Language != nil {
+		lang, err := normalizeLanguage(*input.Language)
+		if err != nil {
+			return err
+		}
+		course.Language = &lang
+	}
+
+	subtitles, err := normalizeLanguages(input.SubtitleLanguages)
+	if err != nil {
+		return err
+	}
+	course.SubtitleLanguages = subtitles
+
+	if input.EstimatedHours != nil {
+		if err := validateEstimatedHours(*input.EstimatedHours); err != nil {
+			return err
+		}
+		hours := *input.EstimatedHours
+		course.EstimatedHours = &hours
+	}
+
+	outcomes, err := normalizeLearningOutcomes(input.LearningOutcomes)
+	if err != nil {
+		return err
+	}
+	course.LearningOutcomes = outcomes
+
+	return nil
+}
+
+// courseMetadataUpdate valida los campos de metadatos presentes en la entrada y
+// devuelve los valores que deben fijarse con $set.
+func courseMetadataUpdate(input model.UpdateCourse) (bson.M, error) {
+	set := bson.M{}
+
+	if input.Level != nil {
+		set["level"] = *input.Level
+	}
+
+	if input.Language != nil {
+		lang, err := normalizeLanguage(*input.Language)
+		if err != nil {
+			return nil, err
+		}
+		set["language"] = lang
+	}
+
+	if input.SubtitleLanguages != nil {
+		subtitles, err := normalizeLanguages(input.SubtitleLanguages)
+		if err != nil {
+			return nil, err
+		}
+		set["subtitlelanguages"] = subtitles
+	}
+
+	if input.EstimatedHours != nil {
+		if err := validateEstimatedHours(*input.EstimatedHours); err != nil {
+			return nil, err
+		}
+		set["estimatedhours"] = *input.EstimatedHours
+	}
+
+	if input.LearningOutcomes != nil {
+		outcomes, err := normalizeLearningOutcomes(input.LearningOutcomes)
+		if err != nil {
+			return nil, err
+		}
+		set["learningoutcomes"] = outcomes
+	}
+
+	return set, nil
+}
+
+func validateEstimatedHours(hours float64) error {
+	if hours <= 0 || hours > maxEstimatedHours {
+		return apperrors.InvalidArgument("estimatedHours must be greater than 0 and at most %d", maxEstimatedHours)
+	}
+	return nil
+}
+
+// normalizeLearningOutcomes valida los objetivos de aprendizaje y quita los espacios sobrantes.
+func normalizeLearningOutcomes(outcomes []string) ([]string, error) {
+	if len(outcomes) > maxLearningOutcomes {
+		return nil, apperrors.InvalidArgument("a course can have at most %d learning outcomes", maxLearningOutcomes)
+	}
+	result := make([]string, 0, len(outcomes))
+	for _, outcome := range outcomes {
+		outcome = strings.TrimSpace(outcome)
+		if outcome == "" {
+			return nil, apperrors.InvalidArgument("learning outcomes must not be empty")
+		}
+		if len(outcome) > maxLearningOutcomeSize {
+			return nil, apperrors.InvalidArgument("learning outcomes must be at most %d characters", maxLearningOutcomeSize)
+		}
+		result = append(result, outcome)
+	}
+	return result, nil
+}
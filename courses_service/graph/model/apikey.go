@@ -0,0 +1,17 @@
+package model
+
+// APIKey es una clave de acceso para llamadas entre servicios, guardada en la
+// colección api_keys. Solo se guarda el hash de la clave.
+type APIKey struct {
+	ID         string  `json:"id" bson:"_id"`
+	Name       string  `json:"name"`
+	Hint       string  `json:"hint"`
+	Hash       string  `json:"-"`
+	Scopes     []Role  `json:"scopes"`
+	CreatedAt  string  `json:"created_at"`
+	ExpiresAt  *string `json:"expiresAt"`
+	RotatedAt  *string `json:"rotatedAt"`
+	RevokedAt  *string `json:"revokedAt"`
+	LastUsedAt *string `json:"lastUsedAt"`
+	UsageCount int     `json:"usageCount"`
+}
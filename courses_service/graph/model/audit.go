@@ -0,0 +1,15 @@
+package model
+
+// AuditEntry es el registro de un campo de mutación en la colección audit_log.
+type AuditEntry struct {
+	ID            string   `json:"id" bson:"_id"`
+	Actor         string   `json:"actor"`
+	Operation     string   `json:"operation"`
+	OperationName *string  `json:"operationName"`
+	Arguments     string   `json:"arguments"`
+	Result        *string  `json:"result"`
+	Success       bool     `json:"success"`
+	Errors        []string `json:"errors"`
+	IP            string   `json:"ip"`
+	Timestamp     string   `json:"timestamp"`
+}
@@ -0,0 +1,21 @@
+package model
+
+// Bundle es un paquete de cursos guardado en la colección bundles.
+type Bundle struct {
+	ID          string   `json:"id" bson:"_id"`
+	Title       string   `json:"title"`
+	Description string   `json:"description"`
+	Price       float64  `json:"price"`
+	CourseIDs   []string `json:"courseIDs"`
+	CreatedAt   string   `json:"created_at"`
+}
+
+// LearningPath es una ruta de aprendizaje guardada en la colección learning_paths.
+type LearningPath struct {
+	ID          string   `json:"id" bson:"_id"`
+	Title       string   `json:"title"`
+	Description string   `json:"description"`
+	Price       float64  `json:"price"`
+	CourseIDs   []string `json:"courseIDs"`
+	CreatedAt   string   `json:"created_at"`
+}
@@ -0,0 +1,11 @@
+package model
+
+// Category es una categoría de cursos guardada en la colección categories.
+type Category struct {
+	ID        string  `json:"id" bson:"_id"`
+	Slug      string  `json:"slug"`
+	Name      string  `json:"name"`
+	ParentID  *string `json:"parentID"`
+	Position  int     `json:"position"`
+	CreatedAt string  `json:"created_at"`
+}
@@ -0,0 +1,14 @@
+package model
+
+// Certificate es el certificado emitido al completar un curso, guardado en la colección certificates.
+type Certificate struct {
+	ID           string `json:"id" bson:"_id"`
+	Code         string `json:"code"`
+	EnrollmentID string `json:"enrollmentID"`
+	CourseID     string `json:"courseID"`
+	CourseTitle  string `json:"courseTitle"`
+	UserID       string `json:"userID"`
+	IssuedAt     string `json:"issued_at"`
+	Algorithm    string `json:"algorithm"`
+	Signature    string `json:"signature"`
+}
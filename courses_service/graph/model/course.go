@@ -0,0 +1,46 @@
+package model
+
+// Course es el curso tal como se guarda en MongoDB y se expone en GraphQL.
+// El ID se guarda como _id para que las búsquedas por ID encuentren el documento.
+type Course struct {
+	ID          string         `json:"id" bson:"_id"`
+	Title       string         `json:"title"`
+	Description string         `json:"description"`
+	CategoryID  *string        `json:"categoryID"`
+	Price       float64        `json:"price"`
+	CreatedAt   string         `json:"created_at"`
+	Prices      []*CoursePrice `json:"prices"`
+	Lessons     []*Lesson      `json:"lessons"`
+	Tags        []string       `json:"tags"`
+	Status      CourseStatus   `json:"status"`
+
+	// Version aumenta con cada edición; las mutaciones de edición la reciben para
+	// rechazar cambios hechos sobre una copia desactualizada.
+	Version int `json:"version"`
+
+	// Translations guarda el título y la descripción en otros idiomas, por código
+	// de idioma. Title y Description están en el idioma original del curso.
+	Translations map[string]*CourseTranslation `json:"translations"`
+
+	Media            []*CourseMedia `json:"media"`
+	ThumbnailMediaID *string        `json:"thumbnailMediaID"`
+
+	Level             *CourseLevel `json:"level"`
+	Language          *string      `json:"language"`
+	SubtitleLanguages []string     `json:"subtitleLanguages"`
+	EstimatedHours    *float64     `json:"estimatedHours"`
+	LearningOutcomes  []string     `json:"learningOutcomes"`
+
+	// PrerequisiteIDs son los cursos que deben completarse antes que este.
+	PrerequisiteIDs      []string `json:"prerequisiteIDs"`
+	EnforcePrerequisites bool     `json:"enforcePrerequisites"`
+
+	// AverageRating y RatingCount se mantienen a partir de las reseñas; RatingSum
+	// es la suma de calificaciones que permite recalcular el promedio.
+	AverageRating float64 `json:"averageRating"`
+	RatingCount   int     `json:"ratingCount"`
+	RatingSum     int     `json:"-"`
+
+	// LocalizedPrice se calcula en cada consulta y no se persiste.
+	LocalizedPrice *LocalizedPrice `json:"localizedPrice,omitempty" bson:"-"`
+}
@@ -0,0 +1,21 @@
+package model
+
+// Lesson es una lección guardada dentro del documento del curso.
+type Lesson struct {
+	ID       string `json:"id"`
+	Title    string `json:"title"`
+	Position int    `json:"position"`
+}
+
+// Enrollment es la inscripción de un usuario en un curso, guardada en la colección enrollments.
+type Enrollment struct {
+	ID               string   `json:"id" bson:"_id"`
+	CourseID         string   `json:"courseID"`
+	UserID           string   `json:"userID"`
+	CompletedLessons []string `json:"completedLessons"`
+	Progress         float64  `json:"progress"`
+	LastLessonID     *string  `json:"lastLessonID,omitempty"`
+	EnrolledAt       string   `json:"enrolled_at"`
+	LastAccessedAt   string   `json:"last_accessed_at"`
+	CompletedAt      *string  `json:"completed_at,omitempty"`
+}
@@ -0,0 +1,28 @@
+package model
+
+// CourseMedia es un archivo subido a un curso, guardado dentro del documento del curso.
+// El archivo en sí está en el BlobStore bajo Key.
+type CourseMedia struct {
+	ID          string    `json:"id"`
+	Kind        MediaKind `json:"kind"`
+	Key         string    `json:"-"`
+	ContentType string    `json:"contentType"`
+	Size        int       `json:"size"`
+	Filename    string    `json:"filename"`
+	UploadedAt  string    `json:"uploaded_at"`
+
+	// ProcessingStatus y Variants solo se usan en imágenes; las variantes las
+	// genera el worker de imágenes después de la subida.
+	ProcessingStatus *MediaProcessingStatus `json:"processingStatus"`
+	Variants         []*ImageVariant        `json:"variants"`
+}
+
+// ImageVariant es una versión redimensionada de una imagen, guardada en el BlobStore bajo Key.
+type ImageVariant struct {
+	Width       int         `json:"width"`
+	Height      int         `json:"height"`
+	Format      ImageFormat `json:"format"`
+	Key         string      `json:"-"`
+	ContentType string      `json:"contentType"`
+	Size        int         `json:"size"`
+}
@@ -2,24 +2,707 @@
 
 package model
 
-type Course struct {
-	ID          string  `json:"id"`
-	Title       string  `json:"title"`
-	Description string  `json:"description"`
-	Category    string  `json:"category"`
-	Price       float64 `json:"price"`
-	CreatedAt   string  `json:"created_at"`
+import (
+	"fmt"
+	"io"
+	"strconv"
+)
+
+type APIKeySecret struct {
+	APIKey *APIKey `json:"apiKey"`
+	Secret string  `json:"secret"`
+}
+
+type AuditLogFilter struct {
+	Actor     *string `json:"actor,omitempty"`
+	Operation *string `json:"operation,omitempty"`
+	Success   *bool   `json:"success,omitempty"`
+	From      *string `json:"from,omitempty"`
+	To        *string `json:"to,omitempty"`
+}
+
+type AuditLogPage struct {
+	Items       []*AuditEntry `json:"items"`
+	TotalCount  int           `json:"totalCount"`
+	EndCursor   *string       `json:"endCursor,omitempty"`
+	HasNextPage bool          `json:"hasNextPage"`
+}
+
+type CartEvent struct {
+	UserID     string     `json:"userID"`
+	Action     CartAction `json:"action"`
+	CourseID   *string    `json:"courseID,omitempty"`
+	BundleID   *string    `json:"bundleID,omitempty"`
+	OccurredAt string     `json:"occurred_at"`
+}
+
+type CategoryFacet struct {
+	CategoryID *string   `json:"categoryID,omitempty"`
+	Category   *Category `json:"category,omitempty"`
+	Count      int       `json:"count"`
+}
+
+type CertificateVerification struct {
+	Valid       bool         `json:"valid"`
+	Certificate *Certificate `json:"certificate,omitempty"`
+}
+
+type CourseFacets struct {
+	Total        int              `json:"total"`
+	Tags         []*FacetCount    `json:"tags"`
+	Categories   []*CategoryFacet `json:"categories"`
+	PriceBuckets []*PriceBucket   `json:"priceBuckets"`
+}
+
+type CourseFilter struct {
+	CategoryID       *string      `json:"categoryID,omitempty"`
+	MinPrice         *float64     `json:"minPrice,omitempty"`
+	MaxPrice         *float64     `json:"maxPrice,omitempty"`
+	MinRating        *float64     `json:"minRating,omitempty"`
+	Tags             []string     `json:"tags,omitempty"`
+	MatchAllTags     *bool        `json:"matchAllTags,omitempty"`
+	Level            *CourseLevel `json:"level,omitempty"`
+	Language         *string      `json:"language,omitempty"`
+	SubtitleLanguage *string      `json:"subtitleLanguage,omitempty"`
+	MinHours         *float64     `json:"minHours,omitempty"`
+	MaxHours         *float64     `json:"maxHours,omitempty"`
+}
+
+type CourseMediaUpload struct {
+	Media  *CourseMedia `json:"media"`
+	Course *Course      `json:"course"`
+}
+
+type CoursePrice struct {
+	Currency string  `json:"currency"`
+	Amount   float64 `json:"amount"`
+}
+
+type CoursePriceInput struct {
+	Currency string  `json:"currency"`
+	Amount   float64 `json:"amount"`
+}
+
+type CourseTranslation struct {
+	Locale      string  `json:"locale"`
+	Title       *string `json:"title,omitempty"`
+	Description *string `json:"description,omitempty"`
+}
+
+type EditReview struct {
+	Rating *int    `json:"rating,omitempty"`
+	Text   *string `json:"text,omitempty"`
+}
+
+type ExchangeRate struct {
+	Currency  string  `json:"currency"`
+	Rate      float64 `json:"rate"`
+	UpdatedAt string  `json:"updated_at"`
+}
+
+type ExchangeRateInput struct {
+	Currency string  `json:"currency"`
+	Rate     float64 `json:"rate"`
+}
+
+type FacetCount struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+type FieldChange struct {
+	Field  string  `json:"field"`
+	Before *string `json:"before,omitempty"`
+	After  *string `json:"after,omitempty"`
+}
+
+type LocalizedPrice struct {
+	Currency string      `json:"currency"`
+	Amount   float64     `json:"amount"`
+	Source   PriceSource `json:"source"`
+	Rate     *float64    `json:"rate,omitempty"`
 }
 
 type Mutation struct {
 }
 
+type NewAPIKey struct {
+	Name      string  `json:"name"`
+	Scopes    []Role  `json:"scopes"`
+	ExpiresAt *string `json:"expiresAt,omitempty"`
+}
+
+type NewBundle struct {
+	Title       string   `json:"title"`
+	Description string   `json:"description"`
+	Price       float64  `json:"price"`
+	CourseIDs   []string `json:"courseIDs"`
+}
+
+type NewCategory struct {
+	Name     string  `json:"name"`
+	Slug     *string `json:"slug,omitempty"`
+	ParentID *string `json:"parentID,omitempty"`
+	Position *int    `json:"position,omitempty"`
+}
+
 type NewCourse struct {
-	Title       string  `json:"title"`
-	Description string  `json:"description"`
-	Category    string  `json:"category"`
-	Price       float64 `json:"price"`
+	Title             string              `json:"title"`
+	Description       string              `json:"description"`
+	CategoryID        string              `json:"categoryID"`
+	Price             float64             `json:"price"`
+	Prices            []*CoursePriceInput `json:"prices,omitempty"`
+	Status            *CourseStatus       `json:"status,omitempty"`
+	Lessons           []*NewLesson        `json:"lessons,omitempty"`
+	Level             *CourseLevel        `json:"level,omitempty"`
+	Language          *string             `json:"language,omitempty"`
+	SubtitleLanguages []string            `json:"subtitleLanguages,omitempty"`
+	EstimatedHours    *float64            `json:"estimatedHours,omitempty"`
+	LearningOutcomes  []string            `json:"learningOutcomes,omitempty"`
+	Tags              []string            `json:"tags,omitempty"`
+}
+
+type NewLearningPath struct {
+	Title       string   `json:"title"`
+	Description string   `json:"description"`
+	Price       float64  `json:"price"`
+	CourseIDs   []string `json:"courseIDs"`
+}
+
+type NewLesson struct {
+	Title string `json:"title"`
+}
+
+type NewReview struct {
+	CourseID string `json:"courseID"`
+	Rating   int    `json:"rating"`
+	Text     string `json:"text"`
+}
+
+type PathCourseProgress struct {
+	Course     *Course     `json:"course"`
+	Enrollment *Enrollment `json:"enrollment,omitempty"`
+}
+
+type PathProgress struct {
+	UserID           string                `json:"userID"`
+	Progress         float64               `json:"progress"`
+	CompletedCourses int                   `json:"completedCourses"`
+	TotalCourses     int                   `json:"totalCourses"`
+	Courses          []*PathCourseProgress `json:"courses"`
+}
+
+type PrerequisiteNode struct {
+	Course        *Course             `json:"course"`
+	Prerequisites []*PrerequisiteNode `json:"prerequisites"`
+}
+
+type PriceBucket struct {
+	Min   float64  `json:"min"`
+	Max   *float64 `json:"max,omitempty"`
+	Count int      `json:"count"`
 }
 
 type Query struct {
 }
+
+type ReviewPage struct {
+	Items       []*Review `json:"items"`
+	TotalCount  int       `json:"totalCount"`
+	EndCursor   *string   `json:"endCursor,omitempty"`
+	HasNextPage bool      `json:"hasNextPage"`
+}
+
+type Subscription struct {
+}
+
+type UpdateCategory struct {
+	Name        *string `json:"name,omitempty"`
+	Slug        *string `json:"slug,omitempty"`
+	ParentID    *string `json:"parentID,omitempty"`
+	ClearParent *bool   `json:"clearParent,omitempty"`
+	Position    *int    `json:"position,omitempty"`
+}
+
+type UpdateCourse struct {
+	Title             *string      `json:"title,omitempty"`
+	Description       *string      `json:"description,omitempty"`
+	CategoryID        *string      `json:"categoryID,omitempty"`
+	Price             *float64     `json:"price,omitempty"`
+	Level             *CourseLevel `json:"level,omitempty"`
+	Language          *string      `json:"language,omitempty"`
+	SubtitleLanguages []string     `json:"subtitleLanguages,omitempty"`
+	EstimatedHours    *float64     `json:"estimatedHours,omitempty"`
+	LearningOutcomes  []string     `json:"learningOutcomes,omitempty"`
+}
+
+type CartAction string
+
+const (
+	CartActionAdded   CartAction = "ADDED"
+	CartActionCleared CartAction = "CLEARED"
+)
+
+var AllCartAction = []CartAction{
+	CartActionAdded,
+	CartActionCleared,
+}
+
+func (e CartAction) IsValid() bool {
+	switch e {
+	case CartActionAdded, CartActionCleared:
+		return true
+	}
+	return false
+}
+
+func (e CartAction) String() string {
+	return string(e)
+}
+
+func (e *CartAction) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = CartAction(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid CartAction", str)
+	}
+	return nil
+}
+
+func (e CartAction) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type CertificateFormat string
+
+const (
+	CertificateFormatPDF CertificateFormat = "PDF"
+	CertificateFormatSVG CertificateFormat = "SVG"
+)
+
+var AllCertificateFormat = []CertificateFormat{
+	CertificateFormatPDF,
+	CertificateFormatSVG,
+}
+
+func (e CertificateFormat) IsValid() bool {
+	switch e {
+	case CertificateFormatPDF, CertificateFormatSVG:
+		return true
+	}
+	return false
+}
+
+func (e CertificateFormat) String() string {
+	return string(e)
+}
+
+func (e *CertificateFormat) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = CertificateFormat(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid CertificateFormat", str)
+	}
+	return nil
+}
+
+func (e CertificateFormat) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type CourseLevel string
+
+const (
+	CourseLevelBeginner     CourseLevel = "BEGINNER"
+	CourseLevelIntermediate CourseLevel = "INTERMEDIATE"
+	CourseLevelAdvanced     CourseLevel = "ADVANCED"
+)
+
+var AllCourseLevel = []CourseLevel{
+	CourseLevelBeginner,
+	CourseLevelIntermediate,
+	CourseLevelAdvanced,
+}
+
+func (e CourseLevel) IsValid() bool {
+	switch e {
+	case CourseLevelBeginner, CourseLevelIntermediate, CourseLevelAdvanced:
+		return true
+	}
+	return false
+}
+
+func (e CourseLevel) String() string {
+	return string(e)
+}
+
+func (e *CourseLevel) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = CourseLevel(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid CourseLevel", str)
+	}
+	return nil
+}
+
+func (e CourseLevel) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type CourseSort string
+
+const (
+	CourseSortNewest     CourseSort = "NEWEST"
+	CourseSortPriceAsc   CourseSort = "PRICE_ASC"
+	CourseSortPriceDesc  CourseSort = "PRICE_DESC"
+	CourseSortRatingDesc CourseSort = "RATING_DESC"
+)
+
+var AllCourseSort = []CourseSort{
+	CourseSortNewest,
+	CourseSortPriceAsc,
+	CourseSortPriceDesc,
+	CourseSortRatingDesc,
+}
+
+func (e CourseSort) IsValid() bool {
+	switch e {
+	case CourseSortNewest, CourseSortPriceAsc, CourseSortPriceDesc, CourseSortRatingDesc:
+		return true
+	}
+	return false
+}
+
+func (e CourseSort) String() string {
+	return string(e)
+}
+
+func (e *CourseSort) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = CourseSort(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid CourseSort", str)
+	}
+	return nil
+}
+
+func (e CourseSort) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type CourseStatus string
+
+const (
+	CourseStatusDraft     CourseStatus = "DRAFT"
+	CourseStatusPublished CourseStatus = "PUBLISHED"
+	CourseStatusArchived  CourseStatus = "ARCHIVED"
+)
+
+var AllCourseStatus = []CourseStatus{
+	CourseStatusDraft,
+	CourseStatusPublished,
+	CourseStatusArchived,
+}
+
+func (e CourseStatus) IsValid() bool {
+	switch e {
+	case CourseStatusDraft, CourseStatusPublished, CourseStatusArchived:
+		return true
+	}
+	return false
+}
+
+func (e CourseStatus) String() string {
+	return string(e)
+}
+
+func (e *CourseStatus) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = CourseStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid CourseStatus", str)
+	}
+	return nil
+}
+
+func (e CourseStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type ImageFormat string
+
+const (
+	ImageFormatWebp ImageFormat = "WEBP"
+	ImageFormatJpeg ImageFormat = "JPEG"
+	ImageFormatPng  ImageFormat = "PNG"
+)
+
+var AllImageFormat = []ImageFormat{
+	ImageFormatWebp,
+	ImageFormatJpeg,
+	ImageFormatPng,
+}
+
+func (e ImageFormat) IsValid() bool {
+	switch e {
+	case ImageFormatWebp, ImageFormatJpeg, ImageFormatPng:
+		return true
+	}
+	return false
+}
+
+func (e ImageFormat) String() string {
+	return string(e)
+}
+
+func (e *ImageFormat) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ImageFormat(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ImageFormat", str)
+	}
+	return nil
+}
+
+func (e ImageFormat) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type MediaKind string
+
+const (
+	MediaKindImage MediaKind = "IMAGE"
+	MediaKindVideo MediaKind = "VIDEO"
+)
+
+var AllMediaKind = []MediaKind{
+	MediaKindImage,
+	MediaKindVideo,
+}
+
+func (e MediaKind) IsValid() bool {
+	switch e {
+	case MediaKindImage, MediaKindVideo:
+		return true
+	}
+	return false
+}
+
+func (e MediaKind) String() string {
+	return string(e)
+}
+
+func (e *MediaKind) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = MediaKind(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid MediaKind", str)
+	}
+	return nil
+}
+
+func (e MediaKind) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type MediaProcessingStatus string
+
+const (
+	MediaProcessingStatusPending MediaProcessingStatus = "PENDING"
+	MediaProcessingStatusReady   MediaProcessingStatus = "READY"
+	MediaProcessingStatusFailed  MediaProcessingStatus = "FAILED"
+)
+
+var AllMediaProcessingStatus = []MediaProcessingStatus{
+	MediaProcessingStatusPending,
+	MediaProcessingStatusReady,
+	MediaProcessingStatusFailed,
+}
+
+func (e MediaProcessingStatus) IsValid() bool {
+	switch e {
+	case MediaProcessingStatusPending, MediaProcessingStatusReady, MediaProcessingStatusFailed:
+		return true
+	}
+	return false
+}
+
+func (e MediaProcessingStatus) String() string {
+	return string(e)
+}
+
+func (e *MediaProcessingStatus) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = MediaProcessingStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid MediaProcessingStatus", str)
+	}
+	return nil
+}
+
+func (e MediaProcessingStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type PriceSource string
+
+const (
+	PriceSourceBase      PriceSource = "BASE"
+	PriceSourceExplicit  PriceSource = "EXPLICIT"
+	PriceSourceConverted PriceSource = "CONVERTED"
+)
+
+var AllPriceSource = []PriceSource{
+	PriceSourceBase,
+	PriceSourceExplicit,
+	PriceSourceConverted,
+}
+
+func (e PriceSource) IsValid() bool {
+	switch e {
+	case PriceSourceBase, PriceSourceExplicit, PriceSourceConverted:
+		return true
+	}
+	return false
+}
+
+func (e PriceSource) String() string {
+	return string(e)
+}
+
+func (e *PriceSource) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = PriceSource(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid PriceSource", str)
+	}
+	return nil
+}
+
+func (e PriceSource) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type RevisionAction string
+
+const (
+	RevisionActionCreate       RevisionAction = "CREATE"
+	RevisionActionUpdate       RevisionAction = "UPDATE"
+	RevisionActionStatusChange RevisionAction = "STATUS_CHANGE"
+	RevisionActionRevert       RevisionAction = "REVERT"
+)
+
+var AllRevisionAction = []RevisionAction{
+	RevisionActionCreate,
+	RevisionActionUpdate,
+	RevisionActionStatusChange,
+	RevisionActionRevert,
+}
+
+func (e RevisionAction) IsValid() bool {
+	switch e {
+	case RevisionActionCreate, RevisionActionUpdate, RevisionActionStatusChange, RevisionActionRevert:
+		return true
+	}
+	return false
+}
+
+func (e RevisionAction) String() string {
+	return string(e)
+}
+
+func (e *RevisionAction) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = RevisionAction(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid RevisionAction", str)
+	}
+	return nil
+}
+
+func (e RevisionAction) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type Role string
+
+const (
+	RoleAdmin      Role = "ADMIN"
+	RoleInstructor Role = "INSTRUCTOR"
+	RoleStudent    Role = "STUDENT"
+)
+
+var AllRole = []Role{
+	RoleAdmin,
+	RoleInstructor,
+	RoleStudent,
+}
+
+func (e Role) IsValid() bool {
+	switch e {
+	case RoleAdmin, RoleInstructor, RoleStudent:
+		return true
+	}
+	return false
+}
+
+func (e Role) String() string {
+	return string(e)
+}
+
+func (e *Role) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = Role(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid Role", str)
+	}
+	return nil
+}
+
+func (e Role) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
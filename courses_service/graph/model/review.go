@@ -0,0 +1,12 @@
+package model
+
+// Review es una reseña de un curso guardada en la colección reviews.
+type Review struct {
+	ID        string `json:"id" bson:"_id"`
+	CourseID  string `json:"courseID"`
+	UserID    string `json:"userID"`
+	Rating    int    `json:"rating"`
+	Text      string `json:"text"`
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
+}
@@ -0,0 +1,14 @@
+package model
+
+// CourseRevision es una copia inmutable de un curso guardada en la colección
+// course_revisions cada vez que se crea o se modifica.
+type CourseRevision struct {
+	ID        string         `json:"id" bson:"_id"`
+	CourseID  string         `json:"courseID"`
+	Number    int            `json:"number"`
+	Action    RevisionAction `json:"action"`
+	Author    string         `json:"author"`
+	CreatedAt string         `json:"created_at"`
+	Changes   []*FieldChange `json:"changes"`
+	Snapshot  *Course        `json:"-"`
+}
@@ -0,0 +1,228 @@
+package graph
+
+import (
+	"context"
+	"courses_service/apperrors"
+	"courses_service/graph/model"
+	"log"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// findCourses busca varios cursos por ID en una sola consulta.
+func (r *Resolver) findCourses(ctx context.Context, ids []string) ([]*model.Course, error) {
+	courses := []*model.Course{}
+	if len(ids) == 0 {
+		return courses, nil
+	}
+
+	cursor, err := r.CourseCollection.Find(ctx, bson.M{"_id": bson.M{"$in": ids}})
+	if err != nil {
+		log.Printf("Failed to find courses: %v", err)
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	if err := cursor.All(ctx, &courses); err != nil {
+		log.Printf("Error decoding courses: %v", err)
+		return nil, err
+	}
+	return courses, nil
+}
+
+// prerequisiteLoader carga los prerrequisitos de varios cursos por ID.
+type prerequisiteLoader func(ctx context.Context, ids []string) ([]*model.Course, error)
+
+// loadPrerequisites carga solo los prerrequisitos de los cursos indicados.
+func (r *Resolver) loadPrerequisites(ctx context.Context, ids []string) ([]*model.Course, error) {
+	cursor, err := r.CourseCollection.Find(ctx,
+		bson.M{"_id": bson.M{"$in": ids}},
+		options.Find().SetProjection(bson.M{"prerequisiteids": 1}),
+	)
+	if err != nil {
+		log.Printf("Failed to load prerequisites: %v", err)
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var courses []*model.Course
+	if err := cursor.All(ctx, &courses); err != nil {
+		log.Printf("Error decoding prerequisites: %v", err)
+		return nil, err
+	}
+	return courses, nil
+}
+
+// prerequisiteGraph recorre los prerrequisitos a partir de los cursos indicados,
+// un nivel por consulta, y devuelve las aristas de todos los cursos alcanzados.
+func (r *Resolver) prerequisiteGraph(ctx context.Context, roots []string) (map[string][]string, error) {
+	return walkPrerequisites(ctx, roots, r.loadPrerequisites)
+}
+
+// walkPrerequisites recorre el grafo de prerrequisitos con load, un nivel por llamada.
+func walkPrerequisites(ctx context.Context, roots []string, load prerequisiteLoader) (map[string][]string, error) {
+	edges := map[string][]string{}
+	pending := roots
+
+	for len(pending) > 0 {
+		courses, err := load(ctx, pending)
+		if err != nil {
+			return nil, err
+		}
+
+		pending = nil
+		for _, course := range courses {
+			edges[course.ID] = course.PrerequisiteIDs
+			for _, id := range course.PrerequisiteIDs {
+				if _, seen := edges[id]; !seen {
+					edges[id] = nil
+					pending = append(pending, id)
+				}
+			}
+		}
+	}
+
+	return edges, nil
+}
+
+// checkPrerequisiteCycle rechaza que courseID dependa de prerequisiteID cuando
+// courseID ya es, directa o indirectamente, un prerrequisito de prerequisiteID.
+// Devuelve los cursos que se consultaron para comprobarlo.
+func (r *Resolver) checkPrerequisiteCycle(ctx context.Context, courseID string, prerequisiteID string) ([]string, error) {
+	return prerequisiteCycle(ctx, courseID, prerequisiteID, r.loadPrerequisites)
+}
+
+// prerequisiteCycle hace la comprobación de checkPrerequisiteCycle cargando los
+// prerrequisitos con load.
+func prerequisiteCycle(ctx context.Context, courseID string, prerequisiteID string, load prerequisiteLoader) ([]string, error) {
+	if courseID == prerequisiteID {
+		return nil, apperrors.InvalidArgument("course %s cannot be its own prerequisite", courseID)
+	}
+
+	edges, err := walkPrerequisites(ctx, []string{prerequisiteID}, load)
+	if err != nil {
+		return nil, err
+	}
+	if _, reachable := edges[courseID]; reachable {
+		return nil, apperrors.Conflict("adding %s as a prerequisite of %s would create a cycle", prerequisiteID, courseID)
+	}
+
+	visited := make([]string, 0, len(edges))
+	for id := range edges {
+		visited = append(visited, id)
+	}
+	return visited, nil
+}
+
+// guardPrerequisites escribe, dentro de la transacción de ctx, el documento
+// guardia de cada curso indicado. Si otra transacción concurrente comprobó o
+// modificó los prerrequisitos de alguno de ellos, ambas escriben el mismo
+// guardia y MongoDB aborta una con un conflicto de escritura; WithTransaction
+// la repite y la comprobación de ciclos vuelve a hacerse con los datos nuevos.
+func (r *Resolver) guardPrerequisites(ctx context.Context, ids []string) error {
+	for _, id := range ids {
+		_, err := r.GuardCollection.UpdateOne(ctx,
+			bson.M{"_id": id},
+			bson.M{"$inc": bson.M{"version": 1}},
+			options.Update().SetUpsert(true),
+		)
+		if err != nil {
+			log.Printf("Failed to guard prerequisites of course %s: %v", id, err)
+			return err
+		}
+	}
+	return nil
+}
+
+// ensurePrerequisiteList guarda una lista vacía en prerequisiteids si el curso
+// lo tiene en null, como los creados antes de los prerrequisitos: $addToSet y
+// $pull fallan sobre un campo null.
+func (r *Resolver) ensurePrerequisiteList(ctx context.Context, courseID string) error {
+	_, err := r.CourseCollection.UpdateOne(ctx,
+		bson.M{"_id": courseID, "prerequisiteids": nil},
+		bson.M{"$set": bson.M{"prerequisiteids": []string{}}},
+	)
+	if err != nil {
+		log.Printf("Failed to initialize prerequisites of course %s: %v", courseID, err)
+	}
+	return err
+}
+
+// buildPrerequisiteTree arma el árbol de prerrequisitos de un curso. Un curso que
+// aparece en varias ramas se repite en cada una.
+func (r *Resolver) buildPrerequisiteTree(ctx context.Context, courseID string) (*model.PrerequisiteNode, error) {
+	edges, err := r.prerequisiteGraph(ctx, []string{courseID})
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(edges))
+	for id := range edges {
+		ids = append(ids, id)
+	}
+	courses, err := r.findCourses(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]*model.Course, len(courses))
+	for _, course := range courses {
+		byID[course.ID] = course
+	}
+
+	var build func(id string, path map[string]bool) *model.PrerequisiteNode
+	build = func(id string, path map[string]bool) *model.PrerequisiteNode {
+		course, ok := byID[id]
+		if !ok {
+			return nil
+		}
+		node := &model.PrerequisiteNode{Course: course, Prerequisites: []*model.PrerequisiteNode{}}
+		// path protege de ciclos que pudieran existir en datos anteriores a la validación
+		path[id] = true
+		for _, prerequisiteID := range course.PrerequisiteIDs {
+			if path[prerequisiteID] {
+				continue
+			}
+			if child := build(prerequisiteID, path); child != nil {
+				node.Prerequisites = append(node.Prerequisites, child)
+			}
+		}
+		delete(path, id)
+		return node
+	}
+
+	return build(courseID, map[string]bool{}), nil
+}
+
+// checkPrerequisitesCompleted comprueba que el usuario completó todos los
+// prerrequisitos del curso, si el curso lo exige. Los cursos en exempt no se exigen.
+func (r *Resolver) checkPrerequisitesCompleted(ctx context.Context, course *model.Course, userID string, exempt map[string]bool) error {
+	if !course.EnforcePrerequisites {
+		return nil
+	}
+
+	required := []string{}
+	for _, id := range course.PrerequisiteIDs {
+		if !exempt[id] {
+			required = append(required, id)
+		}
+	}
+	if len(required) == 0 {
+		return nil
+	}
+
+	completed, err := r.EnrollmentCollection.CountDocuments(ctx, bson.M{
+		"userid":      userID,
+		"courseid":    bson.M{"$in": required},
+		"completedat": bson.M{"$ne": nil},
+	})
+	if err != nil {
+		log.Printf("Failed to count completed prerequisites: %v", err)
+		return err
+	}
+
+	if int(completed) < len(required) {
+		return apperrors.Forbidden("user %s has not completed the prerequisites of course %s", userID, course.ID)
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package graph
+
+import (
+	"context"
+	"courses_service/graph/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Resolver para los prerrequisitos directos de un curso
+func (r *courseResolver) Prerequisites(ctx context.Context, obj *model.Course) ([]*model.Course, error) {
+	return r.loadCourses(ctx, obj.PrerequisiteIDs)
+}
+
+// Mutación para agregar un prerrequisito a un curso, rechazando ciclos. La
+// comprobación y la escritura van en la misma transacción, protegidas por los
+// guardias de los cursos consultados, para que dos cambios simultáneos no
+// puedan cerrar un ciclo entre los dos.
+func (r *mutationResolver) AddPrerequisite(ctx context.Context, courseID string, prerequisiteID string, version int) (*model.Course, error) {
+	if _, err := r.findCourse(ctx, prerequisiteID); err != nil {
+		return nil, err
+	}
+
+	var course *model.Course
+	err := r.withTransaction(ctx, func(ctx mongo.SessionContext) error {
+		visited, err := r.checkPrerequisiteCycle(ctx, courseID, prerequisiteID)
+		if err != nil {
+			return err
+		}
+		if err := r.guardPrerequisites(ctx, append(visited, courseID)); err != nil {
+			return err
+		}
+		if err := r.ensurePrerequisiteList(ctx, courseID); err != nil {
+			return err
+		}
+		course, err = r.updateCourse(ctx, courseID, &version, bson.M{"$addToSet": bson.M{"prerequisiteids": prerequisiteID}}, model.RevisionActionUpdate)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return course, nil
+}
+
+// Mutación para quitar un prerrequisito de un curso
+func (r *mutationResolver) RemovePrerequisite(ctx context.Context, courseID string, prerequisiteID string, version int) (*model.Course, error) {
+	var course *model.Course
+	err := r.withTransaction(ctx, func(ctx mongo.SessionContext) error {
+		if err := r.ensurePrerequisiteList(ctx, courseID); err != nil {
+			return err
+		}
+		var err error
+		course, err = r.updateCourse(ctx, courseID, &version, bson.M{"$pull": bson.M{"prerequisiteids": prerequisiteID}}, model.RevisionActionUpdate)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return course, nil
+}
+
+// Mutación para exigir o no los prerrequisitos al inscribirse en un curso
+func (r *mutationResolver) SetPrerequisiteEnforcement(ctx context.Context, courseID string, enforce bool, version int) (*model.Course, error) {
+	return r.updateCourse(ctx, courseID, &version, bson.M{"$set": bson.M{"enforceprerequisites": enforce}}, model.RevisionActionUpdate)
+}
+
+// Resolver para el árbol completo de prerrequisitos de un curso
+func (r *queryResolver) PrerequisiteTree(ctx context.Context, courseID string) (*model.PrerequisiteNode, error) {
+	return r.buildPrerequisiteTree(ctx, courseID)
+}
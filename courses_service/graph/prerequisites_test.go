@@ -0,0 +1,93 @@
+package graph
+
+import (
+	"context"
+	"courses_service/apperrors"
+	"courses_service/graph/model"
+	"errors"
+	"slices"
+	"testing"
+)
+
+// graphLoader devuelve un prerequisiteLoader que lee los prerrequisitos de un mapa.
+func graphLoader(edges map[string][]string) prerequisiteLoader {
+	return func(ctx context.Context, ids []string) ([]*model.Course, error) {
+		courses := []*model.Course{}
+		for _, id := range ids {
+			if prerequisites, ok := edges[id]; ok {
+				courses = append(courses, &model.Course{ID: id, PrerequisiteIDs: prerequisites})
+			}
+		}
+		return courses, nil
+	}
+}
+
+func TestPrerequisiteCycle(t *testing.T) {
+	// a depende de b, b de c y d, d de e; f tiene la lista en null
+	edges := map[string][]string{
+		"a": {"b"},
+		"b": {"c", "d"},
+		"c": {},
+		"d": {"e"},
+		"e": {},
+		"f": nil,
+	}
+
+	tests := []struct {
+		name         string
+		course       string
+		prerequisite string
+		wantCode     apperrors.Code
+		wantVisited  []string
+	}{
+		{"self", "a", "a", apperrors.CodeInvalidArgument, nil},
+		{"direct cycle", "b", "a", apperrors.CodeConflict, nil},
+		{"indirect cycle", "e", "a", apperrors.CodeConflict, nil},
+		{"no cycle", "a", "d", "", []string{"d", "e"}},
+		{"unrelated course", "f", "b", "", []string{"b", "c", "d", "e"}},
+		{"null prerequisites", "a", "f", "", []string{"f"}},
+		{"missing prerequisite", "a", "x", "", []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			visited, err := prerequisiteCycle(context.Background(), tt.course, tt.prerequisite, graphLoader(edges))
+			if tt.wantCode != "" {
+				if code := apperrors.CodeOf(err); code != tt.wantCode {
+					t.Fatalf("prerequisiteCycle() error = %v, want code %s", err, tt.wantCode)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("prerequisiteCycle() error = %v", err)
+			}
+			slices.Sort(visited)
+			if !slices.Equal(visited, tt.wantVisited) {
+				t.Errorf("prerequisiteCycle() visited %v, want %v", visited, tt.wantVisited)
+			}
+		})
+	}
+}
+
+func TestPrerequisiteCycleExistingCycle(t *testing.T) {
+	// Un ciclo que ya existe en los datos no hace que el recorrido no termine
+	edges := map[string][]string{"a": {"b"}, "b": {"a"}, "c": {}}
+
+	if _, err := prerequisiteCycle(context.Background(), "c", "a", graphLoader(edges)); err != nil {
+		t.Errorf("prerequisiteCycle() error = %v", err)
+	}
+	if _, err := prerequisiteCycle(context.Background(), "a", "b", graphLoader(edges)); apperrors.CodeOf(err) != apperrors.CodeConflict {
+		t.Errorf("prerequisiteCycle() error = %v, want a conflict", err)
+	}
+}
+
+func TestPrerequisiteCycleLoadError(t *testing.T) {
+	failure := errors.New("connection refused")
+	load := func(ctx context.Context, ids []string) ([]*model.Course, error) {
+		return nil, failure
+	}
+
+	if _, err := prerequisiteCycle(context.Background(), "a", "b", load); !errors.Is(err, failure) {
+		t.Errorf("prerequisiteCycle() error = %v, want %v", err, failure)
+	}
+}
@@ -0,0 +1,70 @@
+package graph
+
+import (
+	"context"
+	"courses_service/apperrors"
+	"courses_service/graph/model"
+	"courses_service/pricing"
+	"errors"
+	"log"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// findCourse busca un curso por ID.
+func (r *Resolver) findCourse(ctx context.Context, id string) (*model.Course, error) {
+	var course model.Course
+	err := r.CourseCollection.FindOne(ctx, bson.M{"_id": id}).Decode(&course)
+	if err == mongo.ErrNoDocuments {
+		return nil, apperrors.NotFound("no course found with ID %s", id)
+	}
+	if err != nil {
+		log.Printf("Failed to find course with ID %s: %v", id, err)
+		return nil, err
+	}
+	return &course, nil
+}
+
+// validateCourseID rechaza los IDs que no tienen el formato de los cursos,
+// que se generan como ObjectID en hexadecimal.
+func validateCourseID(id string) error {
+	if !primitive.IsValidObjectID(id) {
+		return apperrors.InvalidArgument("invalid course ID %q", id)
+	}
+	return nil
+}
+
+// localizeCourses rellena LocalizedPrice en los cursos cuando se pidió una moneda.
+// Un curso sin precio en esa moneda ni tipo de cambio queda con LocalizedPrice
+// nulo, sin que falle el resto de la lista.
+func (r *Resolver) localizeCourses(ctx context.Context, currency *string, courses ...*model.Course) error {
+	if currency == nil {
+		return nil
+	}
+	code, err := pricing.NormalizeCurrency(*currency)
+	if err != nil {
+		return err
+	}
+
+	rates, err := pricing.FetchRates(ctx, r.ExchangeRateCollection)
+	if err != nil {
+		log.Printf("Failed to fetch exchange rates: %v", err)
+		return err
+	}
+	table := pricing.RatesTable(rates)
+
+	for _, course := range courses {
+		localized, err := pricing.Localize(course, code, table)
+		if errors.Is(err, pricing.ErrNoRate) {
+			log.Printf("No price in %s for course %s", code, course.ID)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		course.LocalizedPrice = localized
+	}
+	return nil
+}
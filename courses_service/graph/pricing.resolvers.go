@@ -0,0 +1,84 @@
+package graph
+
+import (
+	"context"
+	"courses_service/apperrors"
+	"courses_service/graph/model"
+	"courses_service/pricing"
+	"log"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Mutación para cargar o actualizar tipos de cambio
+func (r *mutationResolver) SetExchangeRates(ctx context.Context, rates []*model.ExchangeRateInput) ([]*model.ExchangeRate, error) {
+	table := make(pricing.Rates, len(rates))
+	for _, input := range rates {
+		currency, err := pricing.NormalizeCurrency(input.Currency)
+		if err != nil {
+			return nil, err
+		}
+		if input.Rate <= 0 {
+			return nil, apperrors.InvalidArgument("exchange rate for %s must be positive", currency)
+		}
+		table[currency] = input.Rate
+	}
+
+	saved, err := pricing.SaveRates(ctx, r.ExchangeRateCollection, table)
+	if err != nil {
+		log.Printf("Failed to save exchange rates: %v", err)
+		return nil, err
+	}
+
+	return saved, nil
+}
+
+// Mutación para fijar el precio de un curso en una moneda
+func (r *mutationResolver) SetCoursePrice(ctx context.Context, courseID string, currency string, amount float64, version int) (*model.Course, error) {
+	course, err := r.findCourse(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+
+	prices, err := pricing.SetPrice(course.Prices, currency, amount)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.updateCoursePrices(ctx, course, prices, version)
+}
+
+// Mutación para quitar el precio de un curso en una moneda
+func (r *mutationResolver) RemoveCoursePrice(ctx context.Context, courseID string, currency string, version int) (*model.Course, error) {
+	course, err := r.findCourse(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+
+	code, err := pricing.NormalizeCurrency(currency)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.updateCoursePrices(ctx, course, pricing.RemovePrice(course.Prices, code), version)
+}
+
+// Resolver para obtener la tabla de tipos de cambio
+func (r *queryResolver) ExchangeRates(ctx context.Context) ([]*model.ExchangeRate, error) {
+	rates, err := pricing.FetchRates(ctx, r.ExchangeRateCollection)
+	if err != nil {
+		log.Printf("Failed to fetch exchange rates: %v", err)
+		return nil, err
+	}
+
+	return rates, nil
+}
+
+func (r *mutationResolver) updateCoursePrices(ctx context.Context, course *model.Course, prices []*model.CoursePrice, version int) (*model.Course, error) {
+	// version es la que leyó el cliente: si el curso cambió desde entonces, o
+	// entre la lectura de course y esta escritura, se rechaza con CONFLICT
+	if course.Version != version {
+		return nil, conflictError(course)
+	}
+	return r.updateCourse(ctx, course.ID, &version, bson.M{"$set": bson.M{"prices": prices}}, model.RevisionActionUpdate)
+}
@@ -1,11 +1,80 @@
 package graph
 
-import "go.mongodb.org/mongo-driver/mongo"
+import (
+	"courses_service/certificates"
+	"courses_service/graph/model"
+	"courses_service/pubsub"
+	"courses_service/storage"
 
-// Resolver es la estructura que contiene la base de datos y la colección de cursos.
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Resolver es la estructura que contiene la base de datos y las colecciones del servicio.
 type Resolver struct {
-	DB               *mongo.Database
-	CourseCollection *mongo.Collection
+	DB                     *mongo.Database
+	CourseCollection       *mongo.Collection
+	ExchangeRateCollection *mongo.Collection
+	ReviewCollection       *mongo.Collection
+	EnrollmentCollection   *mongo.Collection
+	CertificateCollection  *mongo.Collection
+	BundleCollection       *mongo.Collection
+	LearningPathCollection *mongo.Collection
+	CategoryCollection     *mongo.Collection
+	RevisionCollection     *mongo.Collection
+	AuditCollection        *mongo.Collection
+	APIKeyCollection       *mongo.Collection
+	GuardCollection        *mongo.Collection // Guardias de los cambios de prerrequisitos
+
+	// CourseEvents y CartEvents reparten los eventos a las suscripciones
+	// abiertas en esta instancia; los alimenta RunEventBridge.
+	CourseEvents *pubsub.Broker[*model.Course]
+	CartEvents   *pubsub.Broker[*model.CartEvent]
+
+	// BlobStore guarda los archivos multimedia de los cursos.
+	BlobStore storage.BlobStore
+
+	// CertificateSigner firma los certificados; si es nil no se emiten certificados.
+	CertificateSigner certificates.Signer
+}
+
+// Bundle devuelve el resolver para los campos calculados de un paquete.
+func (r *Resolver) Bundle() BundleResolver {
+	return &bundleResolver{r}
+}
+
+// Category devuelve el resolver para los campos calculados de una categoría.
+func (r *Resolver) Category() CategoryResolver {
+	return &categoryResolver{r}
+}
+
+// Certificate devuelve el resolver para los campos calculados de un certificado.
+func (r *Resolver) Certificate() CertificateResolver {
+	return &certificateResolver{r}
+}
+
+// Course devuelve el resolver para los campos calculados de un curso.
+func (r *Resolver) Course() CourseResolver {
+	return &courseResolver{r}
+}
+
+// CourseMedia devuelve el resolver para los campos calculados de un archivo multimedia.
+func (r *Resolver) CourseMedia() CourseMediaResolver {
+	return &courseMediaResolver{r}
+}
+
+// Enrollment devuelve el resolver para los campos calculados de una inscripción.
+func (r *Resolver) Enrollment() EnrollmentResolver {
+	return &enrollmentResolver{r}
+}
+
+// ImageVariant devuelve el resolver para los campos calculados de una variante de imagen.
+func (r *Resolver) ImageVariant() ImageVariantResolver {
+	return &imageVariantResolver{r}
+}
+
+// LearningPath devuelve el resolver para los campos calculados de una ruta de aprendizaje.
+func (r *Resolver) LearningPath() LearningPathResolver {
+	return &learningPathResolver{r}
 }
 
 // Mutation devuelve el resolver para las mutaciones.
@@ -18,8 +87,40 @@ func (r *Resolver) Query() QueryResolver {
 	return &queryResolver{r}
 }
 
+// Subscription devuelve el resolver para las suscripciones.
+func (r *Resolver) Subscription() SubscriptionResolver {
+	return &subscriptionResolver{r}
+}
+
+// bundleResolver es el tipo que implementa los campos calculados de Bundle.
+type bundleResolver struct{ *Resolver }
+
+// categoryResolver es el tipo que implementa los campos calculados de Category.
+type categoryResolver struct{ *Resolver }
+
+// certificateResolver es el tipo que implementa los campos calculados de Certificate.
+type certificateResolver struct{ *Resolver }
+
+// courseResolver es el tipo que implementa los campos calculados de Course.
+type courseResolver struct{ *Resolver }
+
+// courseMediaResolver es el tipo que implementa los campos calculados de CourseMedia.
+type courseMediaResolver struct{ *Resolver }
+
+// enrollmentResolver es el tipo que implementa los campos calculados de Enrollment.
+type enrollmentResolver struct{ *Resolver }
+
+// imageVariantResolver es el tipo que implementa los campos calculados de ImageVariant.
+type imageVariantResolver struct{ *Resolver }
+
+// learningPathResolver es el tipo que implementa los campos calculados de LearningPath.
+type learningPathResolver struct{ *Resolver }
+
 // mutationResolver es el tipo que implementa las mutaciones.
 type mutationResolver struct{ *Resolver }
 
 // queryResolver es el tipo que implementa las consultas.
 type queryResolver struct{ *Resolver }
+
+// subscriptionResolver es el tipo que implementa las suscripciones.
+type subscriptionResolver struct{ *Resolver }
@@ -0,0 +1,72 @@
+package graph
+
+import (
+	"context"
+	"courses_service/apperrors"
+	"courses_service/graph/model"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	maxReviewTextLength = 2000
+	maxReviewsPerPage   = 50
+)
+
+// validateReview comprueba la calificación y el texto de una reseña.
+func validateReview(rating int, text string) error {
+	if rating < 1 || rating > 5 {
+		return apperrors.InvalidArgument("rating must be between 1 and 5, got %d", rating)
+	}
+	if len(strings.TrimSpace(text)) == 0 {
+		return apperrors.InvalidArgument("review text must not be empty")
+	}
+	if len(text) > maxReviewTextLength {
+		return apperrors.InvalidArgument("review text must be at most %d characters", maxReviewTextLength)
+	}
+	return nil
+}
+
+// applyRatingChange ajusta la suma y el número de calificaciones de un curso y
+// recalcula el promedio en una sola actualización, de modo que los tres campos
+// nunca quedan desalineados aunque haya reseñas concurrentes.
+func (r *Resolver) applyRatingChange(ctx context.Context, courseID string, sumDelta int, countDelta int) error {
+	update := mongo.Pipeline{
+		{{Key: "$set", Value: bson.D{
+			{Key: "ratingsum", Value: bson.D{{Key: "$add", Value: bson.A{
+				bson.D{{Key: "$ifNull", Value: bson.A{"$ratingsum", 0}}}, sumDelta,
+			}}}},
+			{Key: "ratingcount", Value: bson.D{{Key: "$add", Value: bson.A{
+				bson.D{{Key: "$ifNull", Value: bson.A{"$ratingcount", 0}}}, countDelta,
+			}}}},
+		}}},
+		{{Key: "$set", Value: bson.D{
+			{Key: "averagerating", Value: bson.D{{Key: "$cond", Value: bson.A{
+				bson.D{{Key: "$gt", Value: bson.A{"$ratingcount", 0}}},
+				bson.D{{Key: "$round", Value: bson.A{
+					bson.D{{Key: "$divide", Value: bson.A{"$ratingsum", "$ratingcount"}}}, 2,
+				}}},
+				0.0,
+			}}}},
+		}}},
+	}
+
+	_, err := r.CourseCollection.UpdateOne(ctx, bson.M{"_id": courseID}, update)
+	return err
+}
+
+// courseSortOrder traduce el orden pedido en GraphQL a un sort de MongoDB.
+func courseSortOrder(sortBy model.CourseSort) bson.D {
+	switch sortBy {
+	case model.CourseSortPriceAsc:
+		return bson.D{{Key: "price", Value: 1}}
+	case model.CourseSortPriceDesc:
+		return bson.D{{Key: "price", Value: -1}}
+	case model.CourseSortRatingDesc:
+		return bson.D{{Key: "averagerating", Value: -1}, {Key: "ratingcount", Value: -1}}
+	default:
+		return bson.D{{Key: "createdat", Value: -1}}
+	}
+}
@@ -0,0 +1,215 @@
+package graph
+
+import (
+	"context"
+	"courses_service/apperrors"
+	"courses_service/graph/model"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Resolver para las reseñas paginadas de un curso, de la más reciente a la más antigua
+func (r *courseResolver) Reviews(ctx context.Context, obj *model.Course, first *int, after *string) (*model.ReviewPage, error) {
+	limit := 10
+	if first != nil {
+		limit = *first
+	}
+	if limit < 1 || limit > maxReviewsPerPage {
+		return nil, apperrors.InvalidArgument("first must be between 1 and %d", maxReviewsPerPage)
+	}
+
+	courseFilter := bson.M{"courseid": obj.ID}
+	total, err := r.ReviewCollection.CountDocuments(ctx, courseFilter)
+	if err != nil {
+		log.Printf("Failed to count reviews of course %s: %v", obj.ID, err)
+		return nil, err
+	}
+
+	filter := bson.M{"courseid": obj.ID}
+	if after != nil {
+		filter["_id"] = bson.M{"$lt": *after}
+	}
+
+	findOptions := options.Find().
+		SetSort(bson.D{{Key: "_id", Value: -1}}).
+		SetLimit(int64(limit + 1))
+	cursor, err := r.ReviewCollection.Find(ctx, filter, findOptions)
+	if err != nil {
+		log.Printf("Failed to find reviews of course %s: %v", obj.ID, err)
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	reviews := []*model.Review{}
+	if err := cursor.All(ctx, &reviews); err != nil {
+		log.Printf("Error decoding reviews: %v", err)
+		return nil, err
+	}
+
+	page := &model.ReviewPage{TotalCount: int(total)}
+	if len(reviews) > limit {
+		reviews = reviews[:limit]
+		page.HasNextPage = true
+	}
+	if len(reviews) > 0 {
+		page.EndCursor = &reviews[len(reviews)-1].ID
+	}
+	page.Items = reviews
+
+	return page, nil
+}
+
+// Mutación para publicar una reseña de un curso
+func (r *mutationResolver) AddReview(ctx context.Context, input model.NewReview) (*model.Review, error) {
+	if err := validateReview(input.Rating, input.Text); err != nil {
+		return nil, err
+	}
+
+	user, err := requireUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := r.findCourse(ctx, input.CourseID); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().Format(time.RFC3339)
+	review := model.Review{
+		ID:        primitive.NewObjectID().Hex(),
+		CourseID:  input.CourseID,
+		UserID:    user.UserID,
+		Rating:    input.Rating,
+		Text:      input.Text,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	// La reseña y el promedio del curso se guardan juntos o no se guardan
+	err = r.withTransaction(ctx, func(ctx mongo.SessionContext) error {
+		if _, err := r.ReviewCollection.InsertOne(ctx, review); err != nil {
+			// El índice único sobre courseid y userid impide reseñar dos veces
+			if mongo.IsDuplicateKeyError(err) {
+				return apperrors.Conflict("user %s already reviewed course %s", user.UserID, input.CourseID)
+			}
+			log.Printf("Failed to insert review: %v", err)
+			return err
+		}
+		if err := r.applyRatingChange(ctx, review.CourseID, review.Rating, 1); err != nil {
+			log.Printf("Failed to update rating of course %s: %v", review.CourseID, err)
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &review, nil
+}
+
+// Mutación para editar la calificación o el texto de una reseña
+func (r *mutationResolver) EditReview(ctx context.Context, id string, input model.EditReview) (*model.Review, error) {
+	var review model.Review
+	if err := r.ReviewCollection.FindOne(ctx, bson.M{"_id": id}).Decode(&review); err != nil {
+		log.Printf("Failed to find review with ID %s: %v", id, err)
+		return nil, err
+	}
+	if _, err := requireOwnerOrAdmin(ctx, review.UserID); err != nil {
+		return nil, err
+	}
+
+	// Solo se validan y escriben los campos enviados, sin partir de la lectura anterior
+	rating, text := review.Rating, review.Text
+	if input.Rating != nil {
+		rating = *input.Rating
+	}
+	if input.Text != nil {
+		text = *input.Text
+	}
+	if err := validateReview(rating, text); err != nil {
+		return nil, err
+	}
+	set := bson.M{"updatedat": time.Now().Format(time.RFC3339)}
+	if input.Rating != nil {
+		set["rating"] = *input.Rating
+	}
+	if input.Text != nil {
+		set["text"] = *input.Text
+	}
+
+	// La diferencia de calificación se calcula con el documento que reemplaza la
+	// actualización, dentro de la misma transacción que ajusta el promedio
+	err := r.withTransaction(ctx, func(ctx mongo.SessionContext) error {
+		var previous model.Review
+		err := r.ReviewCollection.FindOneAndUpdate(ctx,
+			bson.M{"_id": id, "userid": review.UserID},
+			bson.M{"$set": set},
+			options.FindOneAndUpdate().SetReturnDocument(options.Before),
+		).Decode(&previous)
+		if err != nil {
+			log.Printf("Failed to update review with ID %s: %v", id, err)
+			return err
+		}
+
+		review = previous
+		if input.Rating != nil {
+			review.Rating = *input.Rating
+		}
+		if input.Text != nil {
+			review.Text = *input.Text
+		}
+		review.UpdatedAt = set["updatedat"].(string)
+
+		if delta := review.Rating - previous.Rating; delta != 0 {
+			if err := r.applyRatingChange(ctx, review.CourseID, delta, 0); err != nil {
+				log.Printf("Failed to update rating of course %s: %v", review.CourseID, err)
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &review, nil
+}
+
+// Mutación para eliminar una reseña
+func (r *mutationResolver) DeleteReview(ctx context.Context, id string) (*string, error) {
+	var review model.Review
+	if err := r.ReviewCollection.FindOne(ctx, bson.M{"_id": id}).Decode(&review); err != nil {
+		log.Printf("Failed to find review with ID %s: %v", id, err)
+		return nil, err
+	}
+	if _, err := requireOwnerOrAdmin(ctx, review.UserID); err != nil {
+		return nil, err
+	}
+
+	// Se descuenta la calificación del documento borrado, en la misma transacción
+	err := r.withTransaction(ctx, func(ctx mongo.SessionContext) error {
+		var deleted model.Review
+		err := r.ReviewCollection.FindOneAndDelete(ctx, bson.M{"_id": id, "userid": review.UserID}).Decode(&deleted)
+		if err != nil {
+			log.Printf("Failed to delete review with ID %s: %v", id, err)
+			return err
+		}
+		if err := r.applyRatingChange(ctx, deleted.CourseID, -deleted.Rating, -1); err != nil {
+			log.Printf("Failed to update rating of course %s: %v", deleted.CourseID, err)
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response := "Review successfully deleted"
+	return &response, nil
+}
@@ -0,0 +1,196 @@
+package graph
+
+import (
+	"context"
+	"courses_service/apperrors"
+	"courses_service/auth"
+	"courses_service/graph/model"
+	"encoding/json"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// revisionFields son los campos de contenido que se comparan entre revisiones,
+// con su nombre JSON. Las lecciones, los archivos y las calificaciones no se
+// versionan: las inscripciones y el BlobStore dependen de ellos.
+var revisionFields = []string{
+	"title",
+	"description",
+	"categoryID",
+	"price",
+	"prices",
+	"tags",
+	"translations",
+	"level",
+	"language",
+	"subtitleLanguages",
+	"estimatedHours",
+	"learningOutcomes",
+	"prerequisiteIDs",
+	"enforcePrerequisites",
+	"status",
+}
+
+// revisionAuthor identifica al usuario autenticado que hace el cambio.
+func revisionAuthor(ctx context.Context) string {
+	if principal := auth.ForContext(ctx); principal != nil {
+		return principal.UserID
+	}
+	return "anonymous"
+}
+
+// recordRevision guarda una revisión con el estado actual del curso y sus
+// diferencias con la revisión anterior. Los cambios que no tocan campos
+// versionados no generan revisión, salvo la creación. Se llama dentro de la
+// transacción que modifica el curso: como esa transacción escribe el documento
+// del curso, dos cambios simultáneos no pueden leer la misma revisión anterior.
+func (r *Resolver) recordRevision(ctx context.Context, course *model.Course, action model.RevisionAction) error {
+	previous, err := r.latestRevision(ctx, course.ID)
+	if err != nil {
+		log.Printf("Failed to find latest revision of course %s: %v", course.ID, err)
+		return err
+	}
+
+	revision := model.CourseRevision{
+		ID:        primitive.NewObjectID().Hex(),
+		CourseID:  course.ID,
+		Number:    1,
+		Action:    action,
+		Author:    revisionAuthor(ctx),
+		CreatedAt: time.Now().Format(time.RFC3339),
+		Snapshot:  course,
+	}
+	var before *model.Course
+	if previous != nil {
+		revision.Number = previous.Number + 1
+		before = previous.Snapshot
+	}
+	revision.Changes = diffCourses(before, course)
+	if len(revision.Changes) == 0 && action != model.RevisionActionCreate {
+		return nil
+	}
+
+	if _, err := r.RevisionCollection.InsertOne(ctx, revision); err != nil {
+		log.Printf("Failed to save revision of course %s: %v", course.ID, err)
+		return err
+	}
+	return nil
+}
+
+// latestRevision devuelve la última revisión de un curso, o nil si no tiene.
+func (r *Resolver) latestRevision(ctx context.Context, courseID string) (*model.CourseRevision, error) {
+	var revision model.CourseRevision
+	err := r.RevisionCollection.FindOne(ctx,
+		bson.M{"courseid": courseID},
+		options.FindOne().SetSort(bson.D{{Key: "number", Value: -1}}),
+	).Decode(&revision)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &revision, nil
+}
+
+// findRevision busca una revisión de un curso por su número.
+func (r *Resolver) findRevision(ctx context.Context, courseID string, number int) (*model.CourseRevision, error) {
+	var revision model.CourseRevision
+	err := r.RevisionCollection.FindOne(ctx, bson.M{"courseid": courseID, "number": number}).Decode(&revision)
+	if err == mongo.ErrNoDocuments {
+		return nil, apperrors.NotFound("course %s has no revision %d", courseID, number)
+	}
+	if err != nil {
+		log.Printf("Failed to find revision %d of course %s: %v", number, courseID, err)
+		return nil, err
+	}
+	return &revision, nil
+}
+
+// diffCourses compara los campos versionados de dos cursos. Si before es nil
+// se compara con un curso vacío.
+func diffCourses(before *model.Course, after *model.Course) []*model.FieldChange {
+	beforeFields := revisionValues(before)
+	afterFields := revisionValues(after)
+
+	changes := []*model.FieldChange{}
+	for _, field := range revisionFields {
+		b, a := beforeFields[field], afterFields[field]
+		if (b == nil && a == nil) || (b != nil && a != nil && *b == *a) {
+			continue
+		}
+		changes = append(changes, &model.FieldChange{Field: field, Before: b, After: a})
+	}
+	return changes
+}
+
+// revisionValues devuelve el valor en JSON de cada campo versionado. Los campos
+// nulos o vacíos se omiten para que [] y null no cuenten como un cambio.
+func revisionValues(course *model.Course) map[string]*string {
+	values := map[string]*string{}
+	if course == nil {
+		return values
+	}
+
+	data, err := json.Marshal(course)
+	if err != nil {
+		log.Printf("Error marshaling course %s: %v", course.ID, err)
+		return values
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		log.Printf("Error unmarshaling course %s: %v", course.ID, err)
+		return values
+	}
+
+	for _, field := range revisionFields {
+		value := string(fields[field])
+		switch value {
+		case "", "null", "[]", "{}", `""`:
+			continue
+		}
+		values[field] = &value
+	}
+	return values
+}
+
+// revertUpdate devuelve el $set que restaura los campos versionados de una
+// revisión, salvo el estado, que solo cambia con setCourseStatus.
+func revertUpdate(snapshot *model.Course) bson.M {
+	return bson.M{
+		"title":                snapshot.Title,
+		"description":          snapshot.Description,
+		"categoryid":           snapshot.CategoryID,
+		"price":                snapshot.Price,
+		"prices":               nonNil(snapshot.Prices),
+		"tags":                 nonNil(snapshot.Tags),
+		"translations":         nonNilMap(snapshot.Translations),
+		"level":                snapshot.Level,
+		"language":             snapshot.Language,
+		"subtitlelanguages":    nonNil(snapshot.SubtitleLanguages),
+		"estimatedhours":       snapshot.EstimatedHours,
+		"learningoutcomes":     nonNil(snapshot.LearningOutcomes),
+		"prerequisiteids":      nonNil(snapshot.PrerequisiteIDs),
+		"enforceprerequisites": snapshot.EnforcePrerequisites,
+	}
+}
+
+// nonNil evita guardar listas nulas, que romperían $push y $addToSet.
+func nonNil[T any](list []T) []T {
+	if list == nil {
+		return []T{}
+	}
+	return list
+}
+
+func nonNilMap[K comparable, V any](m map[K]V) map[K]V {
+	if m == nil {
+		return map[K]V{}
+	}
+	return m
+}
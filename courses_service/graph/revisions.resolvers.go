@@ -0,0 +1,91 @@
+package graph
+
+import (
+	"context"
+	"courses_service/apperrors"
+	"courses_service/graph/model"
+	"log"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Mutación para restaurar el contenido de un curso a una revisión anterior
+func (r *mutationResolver) RevertCourse(ctx context.Context, id string, revision int, version int) (*model.Course, error) {
+	target, err := r.findRevision(ctx, id, revision)
+	if err != nil {
+		return nil, err
+	}
+	snapshot := target.Snapshot
+
+	// La categoría o los prerrequisitos pueden haber cambiado desde entonces
+	if snapshot.CategoryID != nil {
+		if _, err := r.findCategory(ctx, *snapshot.CategoryID); err != nil {
+			return nil, err
+		}
+	}
+	for _, prerequisiteID := range snapshot.PrerequisiteIDs {
+		if _, err := r.findCourse(ctx, prerequisiteID); err != nil {
+			return nil, apperrors.Conflict("prerequisite %s of revision %d no longer exists", prerequisiteID, revision)
+		}
+	}
+
+	// Los ciclos se comprueban en la misma transacción que restaura el curso,
+	// como en addPrerequisite
+	var course *model.Course
+	err = r.withTransaction(ctx, func(ctx mongo.SessionContext) error {
+		guarded := []string{id}
+		for _, prerequisiteID := range snapshot.PrerequisiteIDs {
+			visited, err := r.checkPrerequisiteCycle(ctx, id, prerequisiteID)
+			if err != nil {
+				return err
+			}
+			guarded = append(guarded, visited...)
+		}
+		if err := r.guardPrerequisites(ctx, guarded); err != nil {
+			return err
+		}
+		course, err = r.updateCourse(ctx, id, &version, bson.M{"$set": revertUpdate(snapshot)}, model.RevisionActionRevert)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return course, nil
+}
+
+// Resolver para el historial de revisiones de un curso
+func (r *queryResolver) CourseRevisions(ctx context.Context, id string) ([]*model.CourseRevision, error) {
+	cursor, err := r.RevisionCollection.Find(ctx,
+		bson.M{"courseid": id},
+		options.Find().SetSort(bson.D{{Key: "number", Value: -1}}),
+	)
+	if err != nil {
+		log.Printf("Failed to find revisions of course %s: %v", id, err)
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	revisions := []*model.CourseRevision{}
+	if err := cursor.All(ctx, &revisions); err != nil {
+		log.Printf("Error decoding revisions: %v", err)
+		return nil, err
+	}
+
+	return revisions, nil
+}
+
+// Resolver para las diferencias entre dos revisiones de un curso
+func (r *queryResolver) CourseRevisionDiff(ctx context.Context, id string, from int, to int) ([]*model.FieldChange, error) {
+	before, err := r.findRevision(ctx, id, from)
+	if err != nil {
+		return nil, err
+	}
+	after, err := r.findRevision(ctx, id, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return diffCourses(before.Snapshot, after.Snapshot), nil
+}
@@ -2,33 +2,45 @@ package graph
 
 import (
 	"context"
+	"courses_service/apperrors"
+	"courses_service/auth"
 	"courses_service/graph/model"
+	"courses_service/pricing"
 	"courses_service/rabbitmq"
-	"fmt"
 	"log"
+	"strings"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
-// Resolver para agregar un curso al carrito
-func (r *mutationResolver) AddToCart(ctx context.Context, courseID string, userID string) (string, error) {
+// Resolver para agregar un curso al carrito del usuario autenticado
+func (r *mutationResolver) AddToCart(ctx context.Context, courseID string) (string, error) {
+	user, err := requireUser(ctx)
+	if err != nil {
+		return "", err
+	}
+
 	// Buscar el curso por ID en MongoDB
 	var course model.Course
-	err := r.CourseCollection.FindOne(ctx, bson.M{"_id": courseID}).Decode(&course)
+	err = r.CourseCollection.FindOne(ctx, bson.M{"_id": courseID}).Decode(&course)
 	if err != nil {
 		log.Printf("Error finding course by ID: %v", err)
 		return "", err
 	}
 
 	// Enviar los detalles del curso a través de RabbitMQ
-	err = rabbitmq.SendCourseDetails(courseID)
+	err = rabbitmq.SendCourseDetails(&course, user.UserID)
 	if err != nil {
 		log.Printf("Failed to publish course details to RabbitMQ: %v", err)
 		return "", err
 	}
 
+	publishCartEvent(user.UserID, model.CartActionAdded, &courseID, nil)
+
 	response := "Course details sent to user service"
 	return response, nil
 }
@@ -37,18 +49,69 @@ func (r *mutationResolver) AddToCart(ctx context.Context, courseID string, userI
 func (r *mutationResolver) CreateCourse(ctx context.Context, input model.NewCourse) (*model.Course, error) {
 	log.Println("Received request to create course")
 
+	if input.Price < 0 {
+		return nil, apperrors.InvalidArgument("price must not be negative")
+	}
+
+	if _, err := r.findCategory(ctx, input.CategoryID); err != nil {
+		return nil, err
+	}
+
 	newCourse := model.Course{
-		ID:          primitive.NewObjectID().Hex(),
-		Title:       input.Title,
-		Description: input.Description,
-		Category:    input.Category,
-		Price:       input.Price,
-		CreatedAt:   time.Now().Format(time.RFC3339),
+		ID:              primitive.NewObjectID().Hex(),
+		Title:           input.Title,
+		Description:     input.Description,
+		CategoryID:      &input.CategoryID,
+		Price:           input.Price,
+		CreatedAt:       time.Now().Format(time.RFC3339),
+		Prices:          []*model.CoursePrice{},
+		Lessons:         []*model.Lesson{},
+		Tags:            []string{},
+		Translations:    map[string]*model.CourseTranslation{},
+		Media:           []*model.CourseMedia{},
+		PrerequisiteIDs: []string{},
+		Status:          model.CourseStatusPublished,
+	}
+	if input.Status != nil {
+		newCourse.Status = *input.Status
+	}
+
+	if len(input.Tags) > 0 {
+		tags, err := normalizeTags(input.Tags)
+		if err != nil {
+			return nil, err
+		}
+		if len(tags) > maxTagsPerCourse {
+			return nil, apperrors.InvalidArgument("a course can have at most %d tags", maxTagsPerCourse)
+		}
+		newCourse.Tags = tags
 	}
 
-	_, err := r.CourseCollection.InsertOne(ctx, newCourse)
+	if err := applyCourseMetadata(&newCourse, input); err != nil {
+		return nil, err
+	}
+
+	for _, l := range input.Lessons {
+		newCourse.Lessons = appendLesson(newCourse.Lessons, l.Title)
+	}
+
+	for _, p := range input.Prices {
+		prices, err := pricing.SetPrice(newCourse.Prices, p.Currency, p.Amount)
+		if err != nil {
+			return nil, err
+		}
+		newCourse.Prices = prices
+	}
+
+	// El curso y su primera revisión se guardan juntos
+	err := r.withTransaction(ctx, func(ctx mongo.SessionContext) error {
+		if _, err := r.CourseCollection.InsertOne(ctx, newCourse); err != nil {
+			log.Printf("Failed to insert new course: %v", err)
+			return err
+		}
+		return r.recordRevision(ctx, &newCourse, model.RevisionActionCreate)
+	})
 	if err != nil {
-		log.Printf("Failed to insert new course: %v", err)
 		return nil, err
 	}
 
@@ -62,16 +125,61 @@ func (r *mutationResolver) CreateCourse(ctx context.Context, input model.NewCour
 	return &newCourse, nil
 }
 
+// Resolver para editar un curso
+func (r *mutationResolver) UpdateCourse(ctx context.Context, id string, input model.UpdateCourse, version int) (*model.Course, error) {
+	set, err := courseMetadataUpdate(input)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.Title != nil {
+		if strings.TrimSpace(*input.Title) == "" {
+			return nil, apperrors.InvalidArgument("title must not be empty")
+		}
+		set["title"] = *input.Title
+	}
+	if input.Description != nil {
+		set["description"] = *input.Description
+	}
+	if input.CategoryID != nil {
+		if _, err := r.findCategory(ctx, *input.CategoryID); err != nil {
+			return nil, err
+		}
+		set["categoryid"] = *input.CategoryID
+	}
+	if input.Price != nil {
+		if *input.Price < 0 {
+			return nil, apperrors.InvalidArgument("price must not be negative")
+		}
+		set["price"] = *input.Price
+	}
+	if len(set) == 0 {
+		course, err := r.findCourse(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if course.Version != version {
+			return nil, conflictError(course)
+		}
+		return course, nil
+	}
+
+	return r.updateCourse(ctx, id, &version, bson.M{"$set": set}, model.RevisionActionUpdate)
+}
+
+// Resolver para cambiar el estado de publicación de un curso
+func (r *mutationResolver) SetCourseStatus(ctx context.Context, id string, status model.CourseStatus, version int) (*model.Course, error) {
+	return r.updateCourse(ctx, id, &version, bson.M{"$set": bson.M{"status": status}}, model.RevisionActionStatusChange)
+}
+
 // Resolver para eliminar un curso
 func (r *mutationResolver) DeleteCourse(ctx context.Context, id string) (*string, error) {
-	objectID, err := primitive.ObjectIDFromHex(id)
-	if err != nil {
-		log.Printf("Invalid ID format: %v", err)
+	if err := validateCourseID(id); err != nil {
 		emptyString := ""
 		return &emptyString, err
 	}
 
-	filter := bson.D{{Key: "_id", Value: objectID}}
+	filter := bson.D{{Key: "_id", Value: id}}
 
 	result, err := r.CourseCollection.DeleteOne(ctx, filter)
 	if err != nil {
@@ -83,7 +191,7 @@ func (r *mutationResolver) DeleteCourse(ctx context.Context, id string) (*string
 	if result.DeletedCount == 0 {
 		log.Printf("No course found with ID %s", id)
 		emptyString := ""
-		return &emptyString, fmt.Errorf("no course found with ID %s", id)
+		return &emptyString, apperrors.NotFound("no course found with ID %s", id)
 	}
 
 	response := "Course successfully deleted"
@@ -97,15 +205,36 @@ func (r *mutationResolver) ClearCart(ctx context.Context) (string, error) {
 		log.Printf("Failed to publish message to RabbitMQ: %v", err)
 		return "", err
 	}
+	if user := auth.ForContext(ctx); user != nil {
+		publishCartEvent(user.UserID, model.CartActionCleared, nil, nil)
+	}
 	response := "Cart cleared"
 	return response, nil
 }
 
-// Resolver para obtener todos los cursos
-func (r *queryResolver) Courses(ctx context.Context) ([]*model.Course, error) {
+// Resolver para el estado de un curso
+func (r *courseResolver) Status(ctx context.Context, obj *model.Course) (model.CourseStatus, error) {
+	if obj.Status == "" {
+		return model.CourseStatusPublished, nil
+	}
+	return obj.Status, nil
+}
+
+// Resolver para obtener todos los cursos, opcionalmente filtrados y ordenados
+func (r *queryResolver) Courses(ctx context.Context, filter *model.CourseFilter, sortBy *model.CourseSort, currency *string) ([]*model.Course, error) {
 	var courses []*model.Course
 
-	cursor, err := r.CourseCollection.Find(ctx, bson.D{})
+	query, err := r.courseFilter(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	findOptions := options.Find()
+	if sortBy != nil {
+		findOptions.SetSort(courseSortOrder(*sortBy))
+	}
+
+	cursor, err := r.CourseCollection.Find(ctx, query, findOptions)
 	if err != nil {
 		log.Printf("Failed to find courses: %v", err)
 		return nil, err
@@ -121,39 +250,59 @@ func (r *queryResolver) Courses(ctx context.Context) ([]*model.Course, error) {
 		courses = append(courses, &course)
 	}
 
+	if err := r.localizeCourses(ctx, currency, courses...); err != nil {
+		return nil, err
+	}
+
 	return courses, nil
 }
 
 // Resolver para obtener un curso por ID
-func (r *queryResolver) Course(ctx context.Context, id string) (*model.Course, error) {
-	var course model.Course
+func (r *queryResolver) Course(ctx context.Context, id string, currency *string) (*model.Course, error) {
+	if err := validateCourseID(id); err != nil {
+		return nil, err
+	}
 
-	filter := bson.D{{Key: "_id", Value: id}}
-	err := r.CourseCollection.FindOne(ctx, filter).Decode(&course)
+	// Un curso que no existe se devuelve como null; solo los fallos reales son errores
+	cached, err := r.loadCourse(ctx, id)
+	if apperrors.CodeOf(err) == apperrors.CodeNotFound {
+		return nil, nil
+	}
 	if err != nil {
-		log.Printf("Failed to find course with ID %s: %v", id, err)
+		return nil, err
+	}
+
+	// Se copia el curso: el del dataloader lo comparten otros campos de la
+	// consulta, que pueden pedir otra moneda
+	course := *cached
+	if err := r.localizeCourses(ctx, currency, &course); err != nil {
 		return nil, err
 	}
 
 	return &course, nil
 }
 
-// Filtro para cursos por categoría y precio
-func (r *queryResolver) FilterCourses(ctx context.Context, category *string, minPrice *float64, maxPrice *float64) ([]*model.Course, error) {
-	var filter bson.D
-
-	if category != nil {
-		filter = append(filter, bson.E{Key: "category", Value: *category})
+// Filtro para cursos por categoría (con sus subcategorías), precio, calificación y etiquetas
+func (r *queryResolver) FilterCourses(ctx context.Context, categoryID *string, minPrice *float64, maxPrice *float64, minRating *float64, tags []string, matchAllTags *bool, sortBy *model.CourseSort, currency *string) ([]*model.Course, error) {
+	filter, err := r.courseFilter(ctx, &model.CourseFilter{
+		CategoryID:   categoryID,
+		MinPrice:     minPrice,
+		MaxPrice:     maxPrice,
+		MinRating:    minRating,
+		Tags:         tags,
+		MatchAllTags: matchAllTags,
+	})
+	if err != nil {
+		return nil, err
 	}
-	if minPrice != nil && maxPrice != nil {
-		filter = append(filter, bson.E{Key: "price", Value: bson.D{
-			{Key: "$gte", Value: *minPrice},
-			{Key: "$lte", Value: *maxPrice},
-		}})
+
+	findOptions := options.Find()
+	if sortBy != nil {
+		findOptions.SetSort(courseSortOrder(*sortBy))
 	}
 
 	var courses []*model.Course
-	cursor, err := r.CourseCollection.Find(ctx, filter)
+	cursor, err := r.CourseCollection.Find(ctx, filter, findOptions)
 	if err != nil {
 		log.Printf("Failed to filter courses: %v", err)
 		return nil, err
@@ -169,5 +318,9 @@ func (r *queryResolver) FilterCourses(ctx context.Context, category *string, min
 		courses = append(courses, &course)
 	}
 
+	if err := r.localizeCourses(ctx, currency, courses...); err != nil {
+		return nil, err
+	}
+
 	return courses, nil
 }
@@ -0,0 +1,86 @@
+package graph
+
+import (
+	"context"
+	"courses_service/graph/model"
+	"courses_service/rabbitmq"
+	"encoding/json"
+	"log"
+	"time"
+)
+
+// Tipo del evento publicado cuando cambia el carrito de un usuario. Los eventos
+// de cursos los publica el watcher de change streams.
+const cartChangedEvent = "cart.changed"
+
+// Temas del broker de cursos
+const (
+	courseCreatedTopic       = "created"
+	courseUpdatedTopicPrefix = "updated:"
+)
+
+// RunEventBridge reenvía a las suscripciones los eventos de cursos y carritos
+// publicados en RabbitMQ, de modo que los reciben los clientes conectados a
+// cualquier instancia. Si se pierde la conexión se vuelve a conectar tras una
+// pausa; termina cuando se cancela ctx.
+func (r *Resolver) RunEventBridge(ctx context.Context) {
+	for ctx.Err() == nil {
+		err := rabbitmq.ConsumeEvents(ctx, []string{"course.*", "cart.*"}, r.dispatchEvent)
+		if ctx.Err() == nil {
+			log.Printf("Event bridge stopped: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+		case <-time.After(5 * time.Second):
+		}
+	}
+}
+
+func (r *Resolver) dispatchEvent(event rabbitmq.ReceivedEvent) {
+	switch event.Type {
+	case rabbitmq.CourseCreatedEvent, rabbitmq.CourseUpdatedEvent:
+		// Se vuelve a leer el curso: el evento no incluye los campos internos
+		// (como las claves de los archivos) que necesitan algunos resolvers
+		var data struct {
+			ID string `json:"id"`
+		}
+		if err := json.Unmarshal(event.Data, &data); err != nil || data.ID == "" {
+			log.Printf("Invalid %s event: %s", event.Type, event.Data)
+			return
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		course, err := r.findCourse(ctx, data.ID)
+		if err != nil {
+			return
+		}
+
+		if event.Type == rabbitmq.CourseCreatedEvent {
+			r.CourseEvents.Publish(courseCreatedTopic, course)
+		}
+		r.CourseEvents.Publish(courseUpdatedTopicPrefix+course.ID, course)
+
+	case cartChangedEvent:
+		var cartEvent model.CartEvent
+		if err := json.Unmarshal(event.Data, &cartEvent); err != nil {
+			log.Printf("Invalid %s event: %s", event.Type, event.Data)
+			return
+		}
+		r.CartEvents.Publish(cartEvent.UserID, &cartEvent)
+	}
+}
+
+// publishCartEvent publica en RabbitMQ un cambio en el carrito de un usuario.
+func publishCartEvent(userID string, action model.CartAction, courseID *string, bundleID *string) {
+	event := model.CartEvent{
+		UserID:     userID,
+		Action:     action,
+		CourseID:   courseID,
+		BundleID:   bundleID,
+		OccurredAt: time.Now().Format(time.RFC3339),
+	}
+	if err := rabbitmq.PublishEvent(cartChangedEvent, event); err != nil {
+		log.Printf("Failed to publish %s event for user %s: %v", cartChangedEvent, userID, err)
+	}
+}
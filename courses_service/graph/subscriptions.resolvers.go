@@ -0,0 +1,27 @@
+package graph
+
+import (
+	"context"
+	"courses_service/graph/model"
+)
+
+// Suscripción a los cursos nuevos
+func (r *subscriptionResolver) CourseCreated(ctx context.Context) (<-chan *model.Course, error) {
+	return r.CourseEvents.Subscribe(ctx, courseCreatedTopic), nil
+}
+
+// Suscripción a los cambios de un curso
+func (r *subscriptionResolver) CourseUpdated(ctx context.Context, id string) (<-chan *model.Course, error) {
+	if _, err := r.findCourse(ctx, id); err != nil {
+		return nil, err
+	}
+	return r.CourseEvents.Subscribe(ctx, courseUpdatedTopicPrefix+id), nil
+}
+
+// Suscripción a los cambios del carrito de un usuario
+func (r *subscriptionResolver) CartChanged(ctx context.Context, userID string) (<-chan *model.CartEvent, error) {
+	if _, err := requireOwnerOrAdmin(ctx, userID); err != nil {
+		return nil, err
+	}
+	return r.CartEvents.Subscribe(ctx, userID), nil
+}
@@ -0,0 +1,101 @@
+package graph
+
+import (
+	"courses_service/apperrors"
+	"strings"
+	"unicode"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	maxTagLength     = 32
+	maxTagsPerCourse = 20
+)
+
+// priceBucketBoundaries son los límites de los rangos de precio de las facetas;
+// los precios a partir del último límite caen en un rango abierto.
+var priceBucketBoundaries = []float64{0, 25, 50, 100, 200}
+
+// normalizeTags pasa las etiquetas a minúsculas, une palabras con guiones y
+// elimina repetidas. Se permiten letras, dígitos y los caracteres + # . -
+func normalizeTags(tags []string) ([]string, error) {
+	seen := map[string]bool{}
+	result := make([]string, 0, len(tags))
+
+	for _, tag := range tags {
+		normalized := strings.Join(strings.Fields(strings.ToLower(tag)), "-")
+		if normalized == "" {
+			return nil, apperrors.InvalidArgument("tags must not be empty")
+		}
+		if len(normalized) > maxTagLength {
+			return nil, apperrors.InvalidArgument("tag %q is longer than %d characters", tag, maxTagLength)
+		}
+		for _, c := range normalized {
+			if !unicode.IsLetter(c) && !unicode.IsDigit(c) && !strings.ContainsRune("+#.-", c) {
+				return nil, apperrors.InvalidArgument("tag %q contains invalid characters", tag)
+			}
+		}
+		if !seen[normalized] {
+			seen[normalized] = true
+			result = append(result, normalized)
+		}
+	}
+	return result, nil
+}
+
+// facetsPipeline calcula en una sola agregación el total y los conteos por
+// etiqueta, categoría y rango de precio de los cursos que cumplen el filtro.
+func facetsPipeline(filter bson.D) mongo.Pipeline {
+	boundaries := bson.A{}
+	for _, b := range priceBucketBoundaries {
+		boundaries = append(boundaries, b)
+	}
+
+	return mongo.Pipeline{
+		{{Key: "$match", Value: filter}},
+		{{Key: "$facet", Value: bson.D{
+			{Key: "total", Value: bson.A{
+				bson.D{{Key: "$count", Value: "count"}},
+			}},
+			{Key: "tags", Value: bson.A{
+				bson.D{{Key: "$unwind", Value: "$tags"}},
+				bson.D{{Key: "$group", Value: bson.D{{Key: "_id", Value: "$tags"}, {Key: "count", Value: bson.D{{Key: "$sum", Value: 1}}}}}},
+				bson.D{{Key: "$sort", Value: bson.D{{Key: "count", Value: -1}, {Key: "_id", Value: 1}}}},
+			}},
+			{Key: "categories", Value: bson.A{
+				bson.D{{Key: "$group", Value: bson.D{{Key: "_id", Value: "$categoryid"}, {Key: "count", Value: bson.D{{Key: "$sum", Value: 1}}}}}},
+				bson.D{{Key: "$sort", Value: bson.D{{Key: "count", Value: -1}}}},
+			}},
+			{Key: "prices", Value: bson.A{
+				bson.D{{Key: "$bucket", Value: bson.D{
+					{Key: "groupBy", Value: "$price"},
+					{Key: "boundaries", Value: boundaries},
+					{Key: "default", Value: "open"},
+					{Key: "output", Value: bson.D{{Key: "count", Value: bson.D{{Key: "$sum", Value: 1}}}}},
+				}}},
+			}},
+		}}},
+	}
+}
+
+// facetsResult es la forma del documento que devuelve facetsPipeline.
+type facetsResult struct {
+	Total []struct {
+		Count int `bson:"count"`
+	} `bson:"total"`
+	Tags []struct {
+		Value string `bson:"_id"`
+		Count int    `bson:"count"`
+	} `bson:"tags"`
+	Categories []struct {
+		CategoryID *string `bson:"_id"`
+		Count      int     `bson:"count"`
+	} `bson:"categories"`
+	Prices []struct {
+		// Límite inferior del rango, o "open" para el último rango
+		Min   interface{} `bson:"_id"`
+		Count int         `bson:"count"`
+	} `bson:"prices"`
+}
@@ -0,0 +1,123 @@
+package graph
+
+import (
+	"context"
+	"courses_service/apperrors"
+	"courses_service/categories"
+	"courses_service/graph/model"
+	"log"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Mutación para agregar etiquetas a un curso
+func (r *mutationResolver) AddCourseTags(ctx context.Context, courseID string, tags []string, version int) (*model.Course, error) {
+	normalized, err := normalizeTags(tags)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(normalized) > maxTagsPerCourse {
+		return nil, apperrors.InvalidArgument("a course can have at most %d tags", maxTagsPerCourse)
+	}
+
+	// El límite se comprueba en el filtro con las etiquetas que tiene el curso al
+	// escribir, no con una lectura previa
+	withinLimit := bson.M{"$expr": bson.M{"$lte": bson.A{
+		bson.M{"$size": bson.M{"$setUnion": bson.A{bson.M{"$ifNull": bson.A{"$tags", bson.A{}}}, normalized}}},
+		maxTagsPerCourse,
+	}}}
+	return r.updateCourseWhere(ctx, courseID, &version,
+		withinLimit, apperrors.InvalidArgument("a course can have at most %d tags", maxTagsPerCourse),
+		bson.M{"$addToSet": bson.M{"tags": bson.M{"$each": normalized}}}, model.RevisionActionUpdate)
+}
+
+// Mutación para quitar etiquetas de un curso
+func (r *mutationResolver) RemoveCourseTags(ctx context.Context, courseID string, tags []string, version int) (*model.Course, error) {
+	normalized, err := normalizeTags(tags)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.updateCourse(ctx, courseID, &version, bson.M{"$pull": bson.M{"tags": bson.M{"$in": normalized}}}, model.RevisionActionUpdate)
+}
+
+// Resolver para los conteos por etiqueta, categoría y rango de precio
+func (r *queryResolver) CourseFacets(ctx context.Context, filter *model.CourseFilter) (*model.CourseFacets, error) {
+	match, err := r.courseFilter(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	cursor, err := r.CourseCollection.Aggregate(ctx, facetsPipeline(match))
+	if err != nil {
+		log.Printf("Failed to aggregate course facets: %v", err)
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var results []facetsResult
+	if err := cursor.All(ctx, &results); err != nil {
+		log.Printf("Error decoding course facets: %v", err)
+		return nil, err
+	}
+
+	facets := &model.CourseFacets{
+		Tags:         []*model.FacetCount{},
+		Categories:   []*model.CategoryFacet{},
+		PriceBuckets: []*model.PriceBucket{},
+	}
+	if len(results) == 0 {
+		return facets, nil
+	}
+	result := results[0]
+
+	if len(result.Total) > 0 {
+		facets.Total = result.Total[0].Count
+	}
+	for _, t := range result.Tags {
+		facets.Tags = append(facets.Tags, &model.FacetCount{Value: t.Value, Count: t.Count})
+	}
+
+	all, err := categories.FetchAll(ctx, r.CategoryCollection)
+	if err != nil {
+		log.Printf("Failed to load categories: %v", err)
+		return nil, err
+	}
+	byID := make(map[string]*model.Category, len(all))
+	for _, c := range all {
+		byID[c.ID] = c
+	}
+	for _, c := range result.Categories {
+		facet := &model.CategoryFacet{CategoryID: c.CategoryID, Count: c.Count}
+		if c.CategoryID != nil {
+			facet.Category = byID[*c.CategoryID]
+		}
+		facets.Categories = append(facets.Categories, facet)
+	}
+
+	// $bucket omite los rangos vacíos; se devuelven todos para que la tienda
+	// pueda mostrar los rangos con cero cursos
+	counts := map[float64]int{}
+	open := 0
+	for _, p := range result.Prices {
+		switch min := p.Min.(type) {
+		case float64:
+			counts[min] = p.Count
+		case int32:
+			counts[float64(min)] = p.Count
+		case int64:
+			counts[float64(min)] = p.Count
+		default:
+			open += p.Count
+		}
+	}
+	last := len(priceBucketBoundaries) - 1
+	for i, min := range priceBucketBoundaries[:last] {
+		max := priceBucketBoundaries[i+1]
+		facets.PriceBuckets = append(facets.PriceBuckets, &model.PriceBucket{Min: min, Max: &max, Count: counts[min]})
+	}
+	facets.PriceBuckets = append(facets.PriceBuckets, &model.PriceBucket{Min: priceBucketBoundaries[last], Count: open})
+
+	return facets, nil
+}
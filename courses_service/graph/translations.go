@@ -0,0 +1,78 @@
+package graph
+
+import (
+	"context"
+	"courses_service/graph/model"
+	"courses_service/i18n"
+	"log"
+	"sort"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// originalLocale es el idioma en el que están Title y Description del curso.
+func originalLocale(course *model.Course) string {
+	if course.Language != nil {
+		return *course.Language
+	}
+	return i18n.Default()
+}
+
+// localizedField resuelve un campo traducible según los idiomas preferidos. Solo
+// se consideran las traducciones que tienen ese campo; si ninguna sirve se usa
+// el idioma original.
+func localizedField(ctx context.Context, course *model.Course, locale *string, field func(*model.CourseTranslation) *string, original string) (string, string, error) {
+	preferred, err := i18n.Preferred(ctx, locale)
+	if err != nil {
+		return "", "", err
+	}
+
+	origin := originalLocale(course)
+	available := []string{origin}
+	for code, t := range course.Translations {
+		if value := field(t); value != nil && *value != "" {
+			available = append(available, code)
+		}
+	}
+	sort.Strings(available[1:])
+
+	chosen := i18n.Resolve(preferred, available, origin)
+	if chosen == origin {
+		return original, origin, nil
+	}
+	return *field(course.Translations[chosen]), chosen, nil
+}
+
+// ensureTranslationMap guarda un documento vacío en translations si el curso lo
+// tiene en null o no lo tiene, como los creados antes de las traducciones: $set
+// sobre "translations.<idioma>" falla cuando translations es null.
+func (r *Resolver) ensureTranslationMap(ctx context.Context, courseID string) error {
+	_, err := r.CourseCollection.UpdateOne(ctx,
+		bson.M{"_id": courseID, "translations": nil},
+		bson.M{"$set": bson.M{"translations": bson.M{}}},
+	)
+	if err != nil {
+		log.Printf("Failed to initialize translations of course %s: %v", courseID, err)
+	}
+	return err
+}
+
+func translationTitle(t *model.CourseTranslation) *string       { return t.Title }
+func translationDescription(t *model.CourseTranslation) *string { return t.Description }
+
+// missingTranslations devuelve los idiomas soportados en los que al curso le
+// falta el título o la descripción.
+func missingTranslations(course *model.Course) []string {
+	origin := originalLocale(course)
+	missing := []string{}
+	for _, locale := range i18n.Supported() {
+		if locale == origin {
+			continue
+		}
+		t := course.Translations[locale]
+		if t == nil || t.Title == nil || *t.Title == "" || t.Description == nil || *t.Description == "" {
+			missing = append(missing, locale)
+		}
+	}
+	return missing
+}
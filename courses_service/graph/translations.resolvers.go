@@ -0,0 +1,153 @@
+package graph
+
+import (
+	"context"
+	"courses_service/apperrors"
+	"courses_service/graph/model"
+	"courses_service/i18n"
+	"log"
+	"sort"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Resolver para el título en el idioma pedido
+func (r *courseResolver) Title(ctx context.Context, obj *model.Course, locale *string) (string, error) {
+	title, _, err := localizedField(ctx, obj, locale, translationTitle, obj.Title)
+	return title, err
+}
+
+// Resolver para la descripción en el idioma pedido
+func (r *courseResolver) Description(ctx context.Context, obj *model.Course, locale *string) (string, error) {
+	description, _, err := localizedField(ctx, obj, locale, translationDescription, obj.Description)
+	return description, err
+}
+
+// Resolver para el idioma en el que se devuelve el título
+func (r *courseResolver) ContentLocale(ctx context.Context, obj *model.Course, locale *string) (string, error) {
+	_, chosen, err := localizedField(ctx, obj, locale, translationTitle, obj.Title)
+	return chosen, err
+}
+
+// Resolver para todas las traducciones de un curso, ordenadas por idioma
+func (r *courseResolver) Translations(ctx context.Context, obj *model.Course) ([]*model.CourseTranslation, error) {
+	translations := make([]*model.CourseTranslation, 0, len(obj.Translations))
+	for locale, t := range obj.Translations {
+		translations = append(translations, &model.CourseTranslation{
+			Locale:      locale,
+			Title:       t.Title,
+			Description: t.Description,
+		})
+	}
+	sort.Slice(translations, func(i, j int) bool { return translations[i].Locale < translations[j].Locale })
+	return translations, nil
+}
+
+// Resolver para los idiomas soportados que le faltan al curso
+func (r *courseResolver) MissingTranslations(ctx context.Context, obj *model.Course) ([]string, error) {
+	return missingTranslations(obj), nil
+}
+
+// Mutación para fijar el título y/o la descripción de un curso en un idioma.
+// Si el idioma es el original del curso se actualizan los campos base.
+func (r *mutationResolver) SetCourseTranslation(ctx context.Context, courseID string, locale string, title *string, description *string, version int) (*model.Course, error) {
+	code, err := i18n.NormalizeLocale(locale)
+	if err != nil {
+		return nil, err
+	}
+	if title == nil && description == nil {
+		return nil, apperrors.InvalidArgument("title or description is required")
+	}
+	if (title != nil && *title == "") || (description != nil && *description == "") {
+		return nil, apperrors.InvalidArgument("translations must not be empty; use removeCourseTranslation instead")
+	}
+
+	course, err := r.findCourse(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+
+	set := bson.M{}
+	if code == originalLocale(course) {
+		if title != nil {
+			set["title"] = *title
+		}
+		if description != nil {
+			set["description"] = *description
+		}
+	} else {
+		set["translations."+code+".locale"] = code
+		if title != nil {
+			set["translations."+code+".title"] = *title
+		}
+		if description != nil {
+			set["translations."+code+".description"] = *description
+		}
+	}
+
+	var updated *model.Course
+	err = r.withTransaction(ctx, func(ctx mongo.SessionContext) error {
+		if err := r.ensureTranslationMap(ctx, courseID); err != nil {
+			return err
+		}
+		var err error
+		updated, err = r.updateCourse(ctx, courseID, &version, bson.M{"$set": set}, model.RevisionActionUpdate)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// Mutación para quitar la traducción de un curso a un idioma
+func (r *mutationResolver) RemoveCourseTranslation(ctx context.Context, courseID string, locale string, version int) (*model.Course, error) {
+	code, err := i18n.NormalizeLocale(locale)
+	if err != nil {
+		return nil, err
+	}
+
+	course, err := r.findCourse(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+	if code == originalLocale(course) {
+		return nil, apperrors.InvalidArgument("cannot remove the original language %s of course %s", code, courseID)
+	}
+
+	return r.updateCourse(ctx, courseID, &version, bson.M{"$unset": bson.M{"translations." + code: ""}}, model.RevisionActionUpdate)
+}
+
+// Resolver para los cursos a los que les falta el título o la descripción en un idioma
+func (r *queryResolver) CoursesMissingTranslation(ctx context.Context, locale string) ([]*model.Course, error) {
+	code, err := i18n.NormalizeLocale(locale)
+	if err != nil {
+		return nil, err
+	}
+
+	cursor, err := r.CourseCollection.Find(ctx, bson.M{"$or": bson.A{
+		bson.M{"translations." + code + ".title": bson.M{"$in": bson.A{nil, ""}}},
+		bson.M{"translations." + code + ".description": bson.M{"$in": bson.A{nil, ""}}},
+	}})
+	if err != nil {
+		log.Printf("Failed to find courses missing %s translation: %v", code, err)
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	courses := []*model.Course{}
+	for cursor.Next(ctx) {
+		var course model.Course
+		if err := cursor.Decode(&course); err != nil {
+			log.Println("Error decoding course:", err)
+			continue
+		}
+		// El idioma original nunca falta
+		if originalLocale(&course) != code {
+			courses = append(courses, &course)
+		}
+	}
+
+	return courses, nil
+}
@@ -0,0 +1,127 @@
+package i18n
+
+import (
+	"context"
+	"courses_service/apperrors"
+	"net/http"
+	"os"
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+// DefaultLocale es el idioma de los cursos que no indican el suyo cuando DEFAULT_LOCALE no está definida.
+const DefaultLocale = "es"
+
+type contextKey struct{}
+
+// NormalizeLocale valida una etiqueta BCP 47 y la devuelve en forma canónica, p. ej. "es_mx" -> "es-MX".
+func NormalizeLocale(locale string) (string, error) {
+	tag, err := language.Parse(strings.ReplaceAll(strings.TrimSpace(locale), "_", "-"))
+	if err != nil || tag == language.Und {
+		return "", apperrors.InvalidArgument("invalid locale %q", locale)
+	}
+	return tag.String(), nil
+}
+
+// BaseLocale devuelve el idioma de una etiqueta sin región, p. ej. "es-MX" -> "es".
+func BaseLocale(locale string) string {
+	base, _ := language.Make(locale).Base()
+	return base.String()
+}
+
+// Default devuelve el idioma por defecto de los cursos.
+func Default() string {
+	if locale, err := NormalizeLocale(os.Getenv("DEFAULT_LOCALE")); err == nil {
+		return locale
+	}
+	return DefaultLocale
+}
+
+// Supported devuelve los idiomas en los que se espera que existan todos los cursos,
+// configurados en SUPPORTED_LOCALES separados por comas (por defecto "es,en").
+func Supported() []string {
+	value := os.Getenv("SUPPORTED_LOCALES")
+	if value == "" {
+		value = "es,en"
+	}
+
+	locales := []string{}
+	for _, part := range strings.Split(value, ",") {
+		if locale, err := NormalizeLocale(part); err == nil {
+			locales = append(locales, locale)
+		}
+	}
+	return locales
+}
+
+// Middleware guarda en el contexto los idiomas preferidos del encabezado
+// Accept-Language, ordenados por su peso q.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header := r.Header.Get("Accept-Language")
+		if header == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		tags, _, err := language.ParseAcceptLanguage(header)
+		if err != nil || len(tags) == 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		preferred := make([]string, 0, len(tags))
+		for _, tag := range tags {
+			preferred = append(preferred, tag.String())
+		}
+		next.ServeHTTP(w, r.WithContext(WithPreferred(r.Context(), preferred)))
+	})
+}
+
+// WithPreferred guarda los idiomas preferidos en el contexto.
+func WithPreferred(ctx context.Context, locales []string) context.Context {
+	return context.WithValue(ctx, contextKey{}, locales)
+}
+
+// Preferred devuelve los idiomas pedidos: el argumento locale tiene prioridad
+// sobre el encabezado Accept-Language. Un locale inválido es un error, no se
+// ignora en favor del encabezado.
+func Preferred(ctx context.Context, locale *string) ([]string, error) {
+	if locale != nil {
+		normalized, err := NormalizeLocale(*locale)
+		if err != nil {
+			return nil, err
+		}
+		return []string{normalized}, nil
+	}
+	preferred, _ := ctx.Value(contextKey{}).([]string)
+	return preferred, nil
+}
+
+// Resolve elige entre los idiomas disponibles el que mejor atiende a los
+// preferidos. Para cada preferido se prueba la etiqueta exacta y luego el idioma
+// base ("es-MX" -> "es", y también cualquier variante "es-*"); si ninguno está
+// disponible se usa fallback.
+func Resolve(preferred []string, available []string, fallback string) string {
+	has := make(map[string]bool, len(available))
+	for _, locale := range available {
+		has[locale] = true
+	}
+
+	for _, locale := range preferred {
+		if has[locale] {
+			return locale
+		}
+		base := BaseLocale(locale)
+		if has[base] {
+			return base
+		}
+		for _, candidate := range available {
+			if BaseLocale(candidate) == base {
+				return candidate
+			}
+		}
+	}
+	return fallback
+}
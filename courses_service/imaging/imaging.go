@@ -0,0 +1,169 @@
+package imaging
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/HugoSmits86/nativewebp"
+	"golang.org/x/image/draw"
+
+	// Decodificadores registrados para image.Decode
+	_ "image/gif"
+
+	_ "golang.org/x/image/webp"
+)
+
+// maxPixels limita el tamaño de las imágenes que se decodifican, para que un
+// archivo pequeño no pueda reservar gigabytes de memoria.
+const maxPixels = 50_000_000
+
+// Format es un formato de salida de las variantes.
+type Format string
+
+const (
+	FormatWebP Format = "webp"
+	FormatJPEG Format = "jpeg"
+	FormatPNG  Format = "png"
+)
+
+// ContentType devuelve el tipo MIME del formato.
+func (f Format) ContentType() string {
+	return "image/" + string(f)
+}
+
+// Ext devuelve la extensión de archivo del formato.
+func (f Format) Ext() string {
+	if f == FormatJPEG {
+		return ".jpg"
+	}
+	return "." + string(f)
+}
+
+// Config define qué variantes se generan de cada imagen.
+type Config struct {
+	// Breakpoints son los anchos en píxeles de las variantes, de menor a mayor.
+	Breakpoints []int
+	Formats     []Format
+	// Quality es la calidad de compresión (1-100) de JPEG. WebP se codifica sin
+	// pérdida, porque no hay un codificador con pérdida en Go puro.
+	Quality int
+}
+
+// LoadConfig lee IMAGE_BREAKPOINTS (por defecto "320,640,1024,1920"),
+// IMAGE_FORMATS (por defecto "webp,jpeg") e IMAGE_QUALITY (por defecto 80).
+func LoadConfig() (Config, error) {
+	config := Config{Quality: 80}
+
+	breakpoints := os.Getenv("IMAGE_BREAKPOINTS")
+	if breakpoints == "" {
+		breakpoints = "320,640,1024,1920"
+	}
+	for _, part := range strings.Split(breakpoints, ",") {
+		width, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil || width <= 0 {
+			return Config{}, fmt.Errorf("invalid IMAGE_BREAKPOINTS value %q", part)
+		}
+		config.Breakpoints = append(config.Breakpoints, width)
+	}
+	sort.Ints(config.Breakpoints)
+
+	formats := os.Getenv("IMAGE_FORMATS")
+	if formats == "" {
+		formats = "webp,jpeg"
+	}
+	for _, part := range strings.Split(formats, ",") {
+		switch format := Format(strings.ToLower(strings.TrimSpace(part))); format {
+		case FormatWebP, FormatJPEG, FormatPNG:
+			config.Formats = append(config.Formats, format)
+		default:
+			return Config{}, fmt.Errorf("invalid IMAGE_FORMATS value %q", part)
+		}
+	}
+
+	if quality := os.Getenv("IMAGE_QUALITY"); quality != "" {
+		q, err := strconv.Atoi(quality)
+		if err != nil || q < 1 || q > 100 {
+			return Config{}, fmt.Errorf("invalid IMAGE_QUALITY value %q", quality)
+		}
+		config.Quality = q
+	}
+
+	return config, nil
+}
+
+// Decode decodifica una imagen JPEG, PNG, GIF o WebP. En los JPEG se aplica la
+// orientación EXIF, ya que los metadatos se descartan al volver a codificar.
+func Decode(r io.Reader) (image.Image, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("Error reading image: %v", err)
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("Error decoding image: %v", err)
+	}
+	if config.Width*config.Height > maxPixels {
+		return nil, fmt.Errorf("image of %dx%d pixels is too large", config.Width, config.Height)
+	}
+
+	img, format, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("Error decoding image: %v", err)
+	}
+	if format == "jpeg" {
+		img = applyOrientation(img, jpegOrientation(data))
+	}
+	return img, nil
+}
+
+// Widths devuelve los anchos de variante para una imagen del ancho indicado. No
+// se amplían imágenes: si es más estrecha que todos los breakpoints se usa su ancho.
+func (c Config) Widths(original int) []int {
+	widths := []int{}
+	for _, w := range c.Breakpoints {
+		if w <= original {
+			widths = append(widths, w)
+		}
+	}
+	if len(widths) == 0 {
+		widths = append(widths, original)
+	}
+	return widths
+}
+
+// Resize escala la imagen al ancho indicado manteniendo la proporción.
+func Resize(img image.Image, width int) image.Image {
+	bounds := img.Bounds()
+	height := bounds.Dy() * width / bounds.Dx()
+	if height < 1 {
+		height = 1
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, bounds, draw.Over, nil)
+	return dst
+}
+
+// Encode codifica la imagen en el formato indicado. Como se parte de los píxeles
+// decodificados, el resultado no conserva EXIF ni otros metadatos del original.
+func Encode(w io.Writer, img image.Image, format Format, quality int) error {
+	switch format {
+	case FormatWebP:
+		return nativewebp.Encode(w, img, nil)
+	case FormatJPEG:
+		return jpeg.Encode(w, img, &jpeg.Options{Quality: quality})
+	case FormatPNG:
+		return png.Encode(w, img)
+	default:
+		return fmt.Errorf("unsupported image format %s", format)
+	}
+}
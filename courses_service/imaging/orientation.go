@@ -0,0 +1,99 @@
+package imaging
+
+import (
+	"encoding/binary"
+	"image"
+)
+
+// jpegOrientation lee la etiqueta Orientation (0x0112) del bloque EXIF de un
+// JPEG. Devuelve 1 (sin transformación) si no existe o no se puede leer.
+func jpegOrientation(data []byte) int {
+	// Recorrer los segmentos hasta encontrar APP1 con el encabezado "Exif\0\0"
+	i := 2
+	for i+4 <= len(data) && data[i] == 0xFF {
+		marker := data[i+1]
+		size := int(binary.BigEndian.Uint16(data[i+2 : i+4]))
+		if marker == 0xDA || i+2+size > len(data) {
+			break
+		}
+		segment := data[i+4 : i+2+size]
+		if marker == 0xE1 && len(segment) > 14 && string(segment[:6]) == "Exif\x00\x00" {
+			return tiffOrientation(segment[6:])
+		}
+		i += 2 + size
+	}
+	return 1
+}
+
+func tiffOrientation(tiff []byte) int {
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+
+	offset := int(order.Uint32(tiff[4:8]))
+	if offset+2 > len(tiff) {
+		return 1
+	}
+	entries := int(order.Uint16(tiff[offset : offset+2]))
+	for n := 0; n < entries; n++ {
+		entry := offset + 2 + n*12
+		if entry+12 > len(tiff) {
+			return 1
+		}
+		if order.Uint16(tiff[entry:entry+2]) == 0x0112 {
+			value := int(order.Uint16(tiff[entry+8 : entry+10]))
+			if value >= 1 && value <= 8 {
+				return value
+			}
+			return 1
+		}
+	}
+	return 1
+}
+
+// applyOrientation rota o refleja la imagen para que se vea como indica la
+// orientación EXIF (valores 1 a 8).
+func applyOrientation(img image.Image, orientation int) image.Image {
+	if orientation <= 1 || orientation > 8 {
+		return img
+	}
+
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	// Las orientaciones 5 a 8 intercambian ancho y alto
+	dw, dh := w, h
+	if orientation >= 5 {
+		dw, dh = h, w
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			var dx, dy int
+			switch orientation {
+			case 2: // espejo horizontal
+				dx, dy = w-1-x, y
+			case 3: // rotación de 180°
+				dx, dy = w-1-x, h-1-y
+			case 4: // espejo vertical
+				dx, dy = x, h-1-y
+			case 5: // transponer
+				dx, dy = y, x
+			case 6: // rotación de 90° en sentido horario
+				dx, dy = h-1-y, x
+			case 7: // transponer opuesto
+				dx, dy = h-1-y, w-1-x
+			case 8: // rotación de 90° en sentido antihorario
+				dx, dy = y, w-1-x
+			}
+			dst.Set(dx, dy, img.At(b.Min.X+x, b.Min.Y+y))
+		}
+	}
+	return dst
+}
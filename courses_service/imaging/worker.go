@@ -0,0 +1,123 @@
+package imaging
+
+import (
+	"bytes"
+	"context"
+	"courses_service/graph/model"
+	"courses_service/rabbitmq"
+	"courses_service/storage"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Worker genera las variantes de las imágenes subidas a los cursos.
+type Worker struct {
+	Config           Config
+	BlobStore        storage.BlobStore
+	CourseCollection *mongo.Collection
+}
+
+// Run consume los trabajos de imagen de RabbitMQ. Si se pierde la conexión se
+// vuelve a conectar tras una pausa; termina cuando se cancela ctx.
+func (w *Worker) Run(ctx context.Context) {
+	for ctx.Err() == nil {
+		err := rabbitmq.ConsumeImageJobs(ctx, func(job rabbitmq.ImageJob) error {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+			defer cancel()
+			return w.Process(ctx, job)
+		})
+		if ctx.Err() == nil {
+			log.Printf("Image worker stopped: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+		case <-time.After(5 * time.Second):
+		}
+	}
+}
+
+// Process genera las variantes de una imagen y las registra en el curso. Si la
+// imagen no se puede procesar, se marca como FAILED; si falla el almacenamiento
+// o la base de datos, devuelve un *rabbitmq.RetryableError para reintentarla.
+func (w *Worker) Process(ctx context.Context, job rabbitmq.ImageJob) error {
+	variants, err := w.generate(ctx, job)
+	if err != nil {
+		var retryable *rabbitmq.RetryableError
+		if !errors.As(err, &retryable) {
+			w.setResult(ctx, job, model.MediaProcessingStatusFailed, []*model.ImageVariant{})
+		}
+		return err
+	}
+	return w.setResult(ctx, job, model.MediaProcessingStatusReady, variants)
+}
+
+func (w *Worker) generate(ctx context.Context, job rabbitmq.ImageJob) ([]*model.ImageVariant, error) {
+	r, err := w.BlobStore.Get(ctx, job.Key)
+	if err == storage.ErrNotFound {
+		return nil, fmt.Errorf("Error reading %s: %v", job.Key, err)
+	}
+	if err != nil {
+		return nil, &rabbitmq.RetryableError{Err: fmt.Errorf("Error reading %s: %v", job.Key, err)}
+	}
+	img, err := Decode(r)
+	r.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	variants := []*model.ImageVariant{}
+	for _, width := range w.Config.Widths(img.Bounds().Dx()) {
+		resized := Resize(img, width)
+		for _, format := range w.Config.Formats {
+			var buf bytes.Buffer
+			if err := Encode(&buf, resized, format, w.Config.Quality); err != nil {
+				return nil, fmt.Errorf("Error encoding %s variant: %v", format, err)
+			}
+
+			variant := &model.ImageVariant{
+				Width:       width,
+				Height:      resized.Bounds().Dy(),
+				Format:      model.ImageFormat(strings.ToUpper(string(format))),
+				Key:         "courses/" + job.CourseID + "/" + job.MediaID + "/" + strconv.Itoa(width) + format.Ext(),
+				ContentType: format.ContentType(),
+				Size:        buf.Len(),
+			}
+			if err := w.BlobStore.Put(ctx, variant.Key, &buf, int64(variant.Size), variant.ContentType); err != nil {
+				return nil, &rabbitmq.RetryableError{Err: err}
+			}
+			variants = append(variants, variant)
+		}
+	}
+	return variants, nil
+}
+
+func (w *Worker) setResult(ctx context.Context, job rabbitmq.ImageJob, status model.MediaProcessingStatus, variants []*model.ImageVariant) error {
+	result, err := w.CourseCollection.UpdateOne(ctx,
+		bson.M{"_id": job.CourseID, "media.id": job.MediaID},
+		bson.M{"$set": bson.M{
+			"media.$.processingstatus": status,
+			"media.$.variants":         variants,
+		}},
+	)
+	if err != nil {
+		log.Printf("Failed to save variants of media %s: %v", job.MediaID, err)
+		return &rabbitmq.RetryableError{Err: err}
+	}
+	// La imagen se eliminó mientras se procesaba: sus variantes quedarían huérfanas
+	if result.MatchedCount == 0 {
+		for _, v := range variants {
+			if err := w.BlobStore.Delete(ctx, v.Key); err != nil {
+				log.Printf("Failed to delete orphan variant %s: %v", v.Key, err)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// collectionIndex es un índice que el servicio necesita en una colección.
+type collectionIndex struct {
+	collection string
+	model      mongo.IndexModel
+}
+
+var indexes = []collectionIndex{
+	// Un usuario solo puede publicar una reseña por curso
+	{"reviews", mongo.IndexModel{
+		Keys:    bson.D{{Key: "courseid", Value: 1}, {Key: "userid", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	}},
+	// Un usuario solo puede inscribirse una vez en cada curso
+	{"enrollments", mongo.IndexModel{
+		Keys:    bson.D{{Key: "courseid", Value: 1}, {Key: "userid", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	}},
+	// Los códigos de verificación de certificados son únicos
+	{"certificates", mongo.IndexModel{
+		Keys:    bson.D{{Key: "code", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	}},
+	// Cada inscripción tiene a lo sumo un certificado
+	{"certificates", mongo.IndexModel{
+		Keys:    bson.D{{Key: "enrollmentid", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	}},
+	// Los slugs de categoría son únicos
+	{"categories", mongo.IndexModel{
+		Keys:    bson.D{{Key: "slug", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	}},
+	// Cada número de revisión aparece una sola vez por curso
+	{"course_revisions", mongo.IndexModel{
+		Keys:    bson.D{{Key: "courseid", Value: 1}, {Key: "number", Value: -1}},
+		Options: options.Index().SetUnique(true),
+	}},
+	// Las API keys se buscan por el hash de la clave
+	{"api_keys", mongo.IndexModel{
+		Keys:    bson.D{{Key: "hash", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	}},
+	// Filtros del registro de auditoría
+	{"audit_log", mongo.IndexModel{Keys: bson.D{{Key: "actor", Value: 1}, {Key: "_id", Value: -1}}}},
+	{"audit_log", mongo.IndexModel{Keys: bson.D{{Key: "operation", Value: 1}, {Key: "_id", Value: -1}}}},
+	{"audit_log", mongo.IndexModel{Keys: bson.D{{Key: "timestamp", Value: -1}}}},
+	// Los persisted queries registrados por los clientes se eliminan al expirar
+	{"persisted_queries", mongo.IndexModel{
+		Keys:    bson.D{{Key: "expiresat", Value: 1}},
+		Options: options.Index().SetExpireAfterSeconds(0),
+	}},
+	// Índice multiclave para filtrar y contar cursos por etiqueta
+	{"courses", mongo.IndexModel{Keys: bson.D{{Key: "tags", Value: 1}}}},
+	// Filtros de los listados por nivel, idioma, subtítulos y duración
+	{"courses", mongo.IndexModel{Keys: bson.D{{Key: "level", Value: 1}}}},
+	{"courses", mongo.IndexModel{Keys: bson.D{{Key: "language", Value: 1}}}},
+	{"courses", mongo.IndexModel{Keys: bson.D{{Key: "subtitlelanguages", Value: 1}}}},
+	{"courses", mongo.IndexModel{Keys: bson.D{{Key: "estimatedhours", Value: 1}}}},
+}
+
+// createIndexes crea los índices que faltan; los existentes no se modifican.
+func createIndexes(ctx context.Context, db *mongo.Database) error {
+	for _, index := range indexes {
+		if _, err := db.Collection(index.collection).Indexes().CreateOne(ctx, index.model); err != nil {
+			return fmt.Errorf("Error creating index on %s: %v", index.collection, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,194 @@
+package limits
+
+import (
+	"context"
+	"courses_service/auth"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/99designs/gqlgen/complexity"
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// Limit es la profundidad y el costo máximo de una operación. Un valor 0 en un
+// límite por cliente toma el valor general.
+type Limit struct {
+	MaxDepth      int `json:"maxDepth"`
+	MaxComplexity int `json:"maxComplexity"`
+}
+
+// Config son los límites generales y los de cada rol y API key.
+type Config struct {
+	Default Limit            `json:"-"`
+	Roles   map[string]Limit `json:"roles"`
+	APIKeys map[string]Limit `json:"apiKeys"`
+}
+
+// LoadConfig lee los límites generales de QUERY_MAX_DEPTH y
+// QUERY_MAX_COMPLEXITY y, si se indica QUERY_LIMITS_FILE, los de cada rol y
+// API key desde un JSON como {"roles": {"ADMIN": {"maxComplexity": 5000}},
+// "apiKeys": {"<id>": {"maxDepth": 15}}}.
+func LoadConfig() (*Config, error) {
+	config := &Config{Default: Limit{MaxDepth: 12, MaxComplexity: 2000}}
+
+	if value := os.Getenv("QUERY_MAX_DEPTH"); value != "" {
+		depth, err := strconv.Atoi(value)
+		if err != nil || depth < 1 {
+			return nil, fmt.Errorf("invalid QUERY_MAX_DEPTH value %q", value)
+		}
+		config.Default.MaxDepth = depth
+	}
+	if value := os.Getenv("QUERY_MAX_COMPLEXITY"); value != "" {
+		cost, err := strconv.Atoi(value)
+		if err != nil || cost < 1 {
+			return nil, fmt.Errorf("invalid QUERY_MAX_COMPLEXITY value %q", value)
+		}
+		config.Default.MaxComplexity = cost
+	}
+
+	if path := os.Getenv("QUERY_LIMITS_FILE"); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("Error reading query limits file: %v", err)
+		}
+		if err := json.Unmarshal(data, config); err != nil {
+			return nil, fmt.Errorf("Error parsing query limits file: %v", err)
+		}
+	}
+
+	// Los roles se comparan sin distinguir mayúsculas, igual que en @hasRole
+	roles := make(map[string]Limit, len(config.Roles))
+	for role, limit := range config.Roles {
+		roles[auth.NormalizeRole(role)] = limit
+	}
+	config.Roles = roles
+
+	return config, nil
+}
+
+// For devuelve los límites del cliente autenticado: los de su API key si están
+// configurados, si no los más permisivos de sus roles y, si no, los generales.
+func (c *Config) For(principal *auth.Principal) Limit {
+	if principal == nil {
+		return c.Default
+	}
+	if principal.APIKeyID != nil {
+		if limit, ok := c.APIKeys[*principal.APIKeyID]; ok {
+			return c.withDefaults(limit)
+		}
+	}
+
+	var best *Limit
+	for _, role := range principal.Roles {
+		limit, ok := c.Roles[auth.NormalizeRole(role)]
+		if !ok {
+			continue
+		}
+		limit = c.withDefaults(limit)
+		if best == nil {
+			best = &limit
+			continue
+		}
+		best.MaxDepth = max(best.MaxDepth, limit.MaxDepth)
+		best.MaxComplexity = max(best.MaxComplexity, limit.MaxComplexity)
+	}
+	if best != nil {
+		return *best
+	}
+	return c.Default
+}
+
+func (c *Config) withDefaults(limit Limit) Limit {
+	if limit.MaxDepth == 0 {
+		limit.MaxDepth = c.Default.MaxDepth
+	}
+	if limit.MaxComplexity == 0 {
+		limit.MaxComplexity = c.Default.MaxComplexity
+	}
+	return limit
+}
+
+// Extension rechaza, antes de ejecutarlas, las operaciones que superan la
+// profundidad o el costo permitidos al cliente. El costo lo calcula el modelo
+// de complejidad del esquema.
+type Extension struct {
+	Config *Config
+
+	es graphql.ExecutableSchema
+}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.OperationContextMutator
+} = &Extension{}
+
+func (e *Extension) ExtensionName() string {
+	return "QueryLimits"
+}
+
+func (e *Extension) Validate(schema graphql.ExecutableSchema) error {
+	e.es = schema
+	return nil
+}
+
+func (e *Extension) MutateOperationContext(ctx context.Context, rc *graphql.OperationContext) *gqlerror.Error {
+	op := rc.Doc.Operations.ForName(rc.OperationName)
+	if op == nil {
+		return nil
+	}
+	limit := e.Config.For(auth.ForContext(ctx))
+
+	if depth := Depth(op.SelectionSet); depth > limit.MaxDepth {
+		return &gqlerror.Error{
+			Message: fmt.Sprintf("operation has depth %d, which exceeds the limit of %d", depth, limit.MaxDepth),
+			Extensions: map[string]interface{}{
+				"code":  "DEPTH_LIMIT_EXCEEDED",
+				"depth": depth,
+				"limit": limit.MaxDepth,
+			},
+		}
+	}
+
+	if cost := complexity.Calculate(e.es, op, rc.Variables); cost > limit.MaxComplexity {
+		return &gqlerror.Error{
+			Message: fmt.Sprintf("operation has complexity %d, which exceeds the limit of %d", cost, limit.MaxComplexity),
+			Extensions: map[string]interface{}{
+				"code":       "COMPLEXITY_LIMIT_EXCEEDED",
+				"complexity": cost,
+				"limit":      limit.MaxComplexity,
+			},
+		}
+	}
+
+	return nil
+}
+
+// Depth devuelve la cantidad máxima de campos anidados de una selección. Los
+// fragmentos no suman un nivel y los campos de introspección no cuentan, para
+// que el playground pueda leer el esquema.
+func Depth(selections ast.SelectionSet) int {
+	deepest := 0
+	for _, selection := range selections {
+		var depth int
+		switch s := selection.(type) {
+		case *ast.Field:
+			if strings.HasPrefix(s.Name, "__") {
+				continue
+			}
+			depth = 1 + Depth(s.SelectionSet)
+		case *ast.InlineFragment:
+			depth = Depth(s.SelectionSet)
+		case *ast.FragmentSpread:
+			if s.Definition != nil {
+				depth = Depth(s.Definition.SelectionSet)
+			}
+		}
+		deepest = max(deepest, depth)
+	}
+	return deepest
+}
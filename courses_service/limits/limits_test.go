@@ -0,0 +1,97 @@
+package limits
+
+import (
+	"courses_service/auth"
+	"testing"
+
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/parser"
+)
+
+func TestDepth(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  int
+	}{
+		{"one field", `{ courses { id } }`, 2},
+		{"deepest branch", `{ courses { id lessons { id } } categories { id } }`, 3},
+		{"scalar only", `{ __typename }`, 0},
+		{"introspection is ignored", `{ __schema { types { fields { type { ofType { name } } } } } }`, 0},
+		{"nested typename is ignored", `{ courses { __typename } }`, 1},
+		{"inline fragments do not add a level", `{ courses { ... on Course { lessons { id } } } }`, 3},
+		{"fragment spreads do not add a level", `query { courses { ...Lessons } } fragment Lessons on Course { lessons { id } }`, 3},
+		{
+			name:  "nested objects",
+			query: `{ bundle(id: "1") { courses { prerequisites { prerequisites { id } } } } }`,
+			want:  5,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := parser.ParseQuery(&ast.Source{Input: tt.query})
+			if err != nil {
+				t.Fatalf("ParseQuery() error = %v", err)
+			}
+			// Sin validar contra el esquema los spreads no tienen Definition
+			for _, op := range doc.Operations {
+				linkFragments(op.SelectionSet, doc.Fragments)
+			}
+			if got := Depth(doc.Operations[0].SelectionSet); got != tt.want {
+				t.Errorf("Depth() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+// linkFragments completa la definición de los spreads, como lo hace la validación.
+func linkFragments(selections ast.SelectionSet, fragments ast.FragmentDefinitionList) {
+	for _, selection := range selections {
+		switch s := selection.(type) {
+		case *ast.Field:
+			linkFragments(s.SelectionSet, fragments)
+		case *ast.InlineFragment:
+			linkFragments(s.SelectionSet, fragments)
+		case *ast.FragmentSpread:
+			s.Definition = fragments.ForName(s.Name)
+			if s.Definition != nil {
+				linkFragments(s.Definition.SelectionSet, fragments)
+			}
+		}
+	}
+}
+
+func TestConfigFor(t *testing.T) {
+	keyID := "key-1"
+	config := &Config{
+		Default: Limit{MaxDepth: 10, MaxComplexity: 1000},
+		Roles: map[string]Limit{
+			"ADMIN":      {MaxDepth: 20, MaxComplexity: 5000},
+			"INSTRUCTOR": {MaxComplexity: 3000},
+		},
+		APIKeys: map[string]Limit{keyID: {MaxDepth: 15}},
+	}
+
+	tests := []struct {
+		name      string
+		principal *auth.Principal
+		want      Limit
+	}{
+		{"anonymous", nil, Limit{MaxDepth: 10, MaxComplexity: 1000}},
+		{"role without limits", &auth.Principal{Roles: []string{"GUEST"}}, Limit{MaxDepth: 10, MaxComplexity: 1000}},
+		{"role", &auth.Principal{Roles: []string{"ADMIN"}}, Limit{MaxDepth: 20, MaxComplexity: 5000}},
+		{"role in lowercase", &auth.Principal{Roles: []string{"admin"}}, Limit{MaxDepth: 20, MaxComplexity: 5000}},
+		{"missing values use the defaults", &auth.Principal{Roles: []string{"INSTRUCTOR"}}, Limit{MaxDepth: 10, MaxComplexity: 3000}},
+		{"most permissive role", &auth.Principal{Roles: []string{"INSTRUCTOR", "ADMIN"}}, Limit{MaxDepth: 20, MaxComplexity: 5000}},
+		{"API key", &auth.Principal{Roles: []string{"ADMIN"}, APIKeyID: &keyID}, Limit{MaxDepth: 15, MaxComplexity: 1000}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := config.For(tt.principal); got != tt.want {
+				t.Errorf("For() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,158 @@
+package persisted
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Mode indica qué se hace con las operaciones que no están en el manifiesto.
+type Mode string
+
+const (
+	// ModeOff ejecuta cualquier operación.
+	ModeOff Mode = "off"
+	// ModeReport ejecuta cualquier operación, pero registra las que no están en el manifiesto.
+	ModeReport Mode = "report"
+	// ModeEnforce rechaza las operaciones que no están en el manifiesto.
+	ModeEnforce Mode = "enforce"
+)
+
+// Config es la configuración de los persisted queries y la lista de operaciones permitidas.
+type Config struct {
+	Manifest *Manifest
+	Mode     Mode
+	// UseStore guarda los persisted queries en MongoDB además de en memoria.
+	UseStore bool
+	// StoreTTL es cuánto se conserva en MongoDB un persisted query registrado por un cliente.
+	StoreTTL time.Duration
+	// StoreMax es la cantidad máxima de persisted queries guardados en MongoDB.
+	StoreMax int64
+}
+
+// LoadConfig lee la configuración de las variables de entorno:
+//
+//   - OPERATION_MANIFEST_FILE: manifiesto de operaciones permitidas.
+//   - OPERATION_ALLOWLIST_MODE: "off" (por defecto), "report" o "enforce".
+//     Con APP_ENV=development, "enforce" se comporta como "report" para que el
+//     playground pueda ejecutar consultas nuevas.
+//   - PERSISTED_QUERY_STORE: "memory" (por defecto) o "mongo".
+//   - PERSISTED_QUERY_TTL: duración de los persisted queries guardados en
+//     MongoDB (por defecto 24h).
+//   - PERSISTED_QUERY_STORE_MAX: cantidad máxima de persisted queries guardados
+//     en MongoDB (por defecto 10000).
+func LoadConfig() (Config, error) {
+	config := Config{Mode: ModeOff, StoreTTL: 24 * time.Hour, StoreMax: 10000}
+
+	if path := os.Getenv("OPERATION_MANIFEST_FILE"); path != "" {
+		manifest, err := LoadManifest(path)
+		if err != nil {
+			return Config{}, err
+		}
+		config.Manifest = manifest
+	}
+
+	switch mode := Mode(os.Getenv("OPERATION_ALLOWLIST_MODE")); mode {
+	case "", ModeOff:
+	case ModeReport, ModeEnforce:
+		if config.Manifest == nil {
+			return Config{}, fmt.Errorf("OPERATION_ALLOWLIST_MODE %q requires OPERATION_MANIFEST_FILE", mode)
+		}
+		config.Mode = mode
+	default:
+		return Config{}, fmt.Errorf("unknown OPERATION_ALLOWLIST_MODE %q", mode)
+	}
+	if config.Mode == ModeEnforce && os.Getenv("APP_ENV") == "development" {
+		log.Printf("APP_ENV is development: operations missing from the manifest are reported instead of rejected")
+		config.Mode = ModeReport
+	}
+
+	switch store := os.Getenv("PERSISTED_QUERY_STORE"); store {
+	case "", "memory":
+	case "mongo":
+		config.UseStore = true
+	default:
+		return Config{}, fmt.Errorf("unknown PERSISTED_QUERY_STORE %q", store)
+	}
+	if value := os.Getenv("PERSISTED_QUERY_TTL"); value != "" {
+		ttl, err := time.ParseDuration(value)
+		if err != nil || ttl <= 0 {
+			return Config{}, fmt.Errorf("invalid PERSISTED_QUERY_TTL value %q", value)
+		}
+		config.StoreTTL = ttl
+	}
+	if value := os.Getenv("PERSISTED_QUERY_STORE_MAX"); value != "" {
+		max, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || max < 1 {
+			return Config{}, fmt.Errorf("invalid PERSISTED_QUERY_STORE_MAX value %q", value)
+		}
+		config.StoreMax = max
+	}
+
+	return config, nil
+}
+
+// NewCache crea la caché de persisted queries de la configuración. Cuando se
+// rechazan las operaciones desconocidas, tampoco se guardan.
+func (c Config) NewCache(collection *mongo.Collection) *Cache {
+	var store *Store
+	if c.UseStore {
+		store = &Store{Collection: collection, TTL: c.StoreTTL, Max: c.StoreMax}
+	}
+	var accept func(string) bool
+	if c.Mode == ModeEnforce {
+		accept = c.Manifest.Contains
+	}
+	return NewCache(c.Manifest, store, accept)
+}
+
+// Allowlist comprueba que cada operación esté en el manifiesto. Se evalúa
+// después de los persisted queries automáticos, así que da igual si el cliente
+// envió el texto o solo el hash.
+type Allowlist struct {
+	Manifest *Manifest
+	Mode     Mode
+}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.OperationContextMutator
+} = &Allowlist{}
+
+func (a *Allowlist) ExtensionName() string {
+	return "OperationAllowlist"
+}
+
+func (a *Allowlist) Validate(schema graphql.ExecutableSchema) error {
+	return nil
+}
+
+func (a *Allowlist) MutateOperationContext(ctx context.Context, rc *graphql.OperationContext) *gqlerror.Error {
+	if a.Mode == ModeOff {
+		return nil
+	}
+
+	hash := Hash(rc.RawQuery)
+	if a.Manifest.Contains(hash) {
+		return nil
+	}
+
+	if a.Mode == ModeReport {
+		log.Printf("Operation %q (%s) is not in the manifest", rc.OperationName, hash)
+		return nil
+	}
+	return &gqlerror.Error{
+		Message: "operation is not in the list of allowed operations",
+		Extensions: map[string]interface{}{
+			"code": "OPERATION_NOT_ALLOWED",
+			"hash": hash,
+		},
+	}
+}
@@ -0,0 +1,126 @@
+package persisted
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+)
+
+func TestAllowlist(t *testing.T) {
+	manifest := &Manifest{operations: map[string]string{Hash(courseQuery): courseQuery}}
+
+	tests := []struct {
+		name      string
+		mode      Mode
+		query     string
+		wantError bool
+	}{
+		{name: "off allows unknown operations", mode: ModeOff, query: coursesQuery},
+		{name: "report allows unknown operations", mode: ModeReport, query: coursesQuery},
+		{name: "enforce allows operations in the manifest", mode: ModeEnforce, query: courseQuery},
+		{name: "enforce rejects unknown operations", mode: ModeEnforce, query: coursesQuery, wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			allowlist := &Allowlist{Manifest: manifest, Mode: tt.mode}
+			err := allowlist.MutateOperationContext(context.Background(), &graphql.OperationContext{RawQuery: tt.query})
+			if (err != nil) != tt.wantError {
+				t.Fatalf("MutateOperationContext() error = %v, want error %v", err, tt.wantError)
+			}
+			if err != nil {
+				if err.Extensions["code"] != "OPERATION_NOT_ALLOWED" || err.Extensions["hash"] != Hash(tt.query) {
+					t.Errorf("MutateOperationContext() extensions = %v, want code and hash", err.Extensions)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	manifestPath := writeManifest(t, `{"operations": [{"id": "`+Hash(coursesQuery)+`", "name": "Courses"}]}`)
+
+	tests := []struct {
+		name    string
+		env     map[string]string
+		want    Config
+		wantErr bool
+	}{
+		{
+			name: "defaults",
+			want: Config{Mode: ModeOff, StoreTTL: 24 * time.Hour, StoreMax: 10000},
+		},
+		{
+			name: "enforce with manifest and mongo store",
+			env: map[string]string{
+				"OPERATION_MANIFEST_FILE":   manifestPath,
+				"OPERATION_ALLOWLIST_MODE":  "enforce",
+				"PERSISTED_QUERY_STORE":     "mongo",
+				"PERSISTED_QUERY_TTL":       "1h",
+				"PERSISTED_QUERY_STORE_MAX": "50",
+			},
+			want: Config{Mode: ModeEnforce, UseStore: true, StoreTTL: time.Hour, StoreMax: 50},
+		},
+		{
+			name: "enforce only reports in development",
+			env: map[string]string{
+				"OPERATION_MANIFEST_FILE":  manifestPath,
+				"OPERATION_ALLOWLIST_MODE": "enforce",
+				"APP_ENV":                  "development",
+			},
+			want: Config{Mode: ModeReport, StoreTTL: 24 * time.Hour, StoreMax: 10000},
+		},
+		{
+			name:    "mode requires a manifest",
+			env:     map[string]string{"OPERATION_ALLOWLIST_MODE": "report"},
+			wantErr: true,
+		},
+		{
+			name:    "unknown mode",
+			env:     map[string]string{"OPERATION_ALLOWLIST_MODE": "strict"},
+			wantErr: true,
+		},
+		{
+			name:    "unknown store",
+			env:     map[string]string{"PERSISTED_QUERY_STORE": "redis"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid TTL",
+			env:     map[string]string{"PERSISTED_QUERY_TTL": "-1h"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid store max",
+			env:     map[string]string{"PERSISTED_QUERY_STORE_MAX": "0"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, key := range []string{"OPERATION_MANIFEST_FILE", "OPERATION_ALLOWLIST_MODE", "APP_ENV", "PERSISTED_QUERY_STORE", "PERSISTED_QUERY_TTL", "PERSISTED_QUERY_STORE_MAX"} {
+				t.Setenv(key, tt.env[key])
+			}
+
+			got, err := LoadConfig()
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("LoadConfig() = %+v, want an error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LoadConfig() error = %v", err)
+			}
+			if got.Mode != tt.want.Mode || got.UseStore != tt.want.UseStore || got.StoreTTL != tt.want.StoreTTL || got.StoreMax != tt.want.StoreMax {
+				t.Errorf("LoadConfig() = %+v, want %+v", got, tt.want)
+			}
+			if (got.Manifest != nil) != (tt.env["OPERATION_MANIFEST_FILE"] != "") {
+				t.Errorf("LoadConfig() manifest = %v", got.Manifest)
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package persisted
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/handler/lru"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// lruSize es la cantidad de operaciones que se guardan en memoria.
+const lruSize = 1000
+
+// Cache es la caché de persisted queries automáticos. Busca primero en memoria,
+// después en el manifiesto y por último en Store, si está configurado, de modo
+// que las operaciones registradas por un cliente sobreviven a los reinicios y
+// las comparten todas las instancias.
+type Cache struct {
+	Manifest *Manifest
+	Store    *Store
+	// Accept decide qué operaciones se guardan; si es nil se guardan todas.
+	Accept func(hash string) bool
+
+	lru graphql.Cache[string]
+}
+
+var _ graphql.Cache[string] = &Cache{}
+
+// NewCache crea la caché. store puede ser nil.
+func NewCache(manifest *Manifest, store *Store, accept func(hash string) bool) *Cache {
+	return &Cache{
+		Manifest: manifest,
+		Store:    store,
+		Accept:   accept,
+		lru:      lru.New[string](lruSize),
+	}
+}
+
+func (c *Cache) Get(ctx context.Context, hash string) (string, bool) {
+	if query, ok := c.lru.Get(ctx, hash); ok {
+		return query, true
+	}
+	if query, ok := c.Manifest.Query(hash); ok {
+		c.lru.Add(ctx, hash, query)
+		return query, true
+	}
+	if c.Store == nil {
+		return "", false
+	}
+	query, ok := c.Store.Get(ctx, hash)
+	if ok {
+		c.lru.Add(ctx, hash, query)
+	}
+	return query, ok
+}
+
+func (c *Cache) Add(ctx context.Context, hash string, query string) {
+	if c.Accept != nil && !c.Accept(hash) {
+		return
+	}
+	c.lru.Add(ctx, hash, query)
+	if c.Store != nil {
+		c.Store.Add(ctx, hash, query)
+	}
+}
+
+// Store guarda los persisted queries en MongoDB. Cualquier cliente puede
+// registrar operaciones, así que cada una expira después de TTL (con el índice
+// TTL sobre expiresat) y no se guardan más de Max; las que no entran siguen
+// funcionando desde la memoria de la instancia.
+type Store struct {
+	Collection *mongo.Collection
+	TTL        time.Duration
+	Max        int64
+}
+
+// storedQuery es el documento de la colección persisted_queries.
+type storedQuery struct {
+	Hash      string    `bson:"_id"`
+	Query     string    `bson:"query"`
+	CreatedAt string    `bson:"createdat"`
+	ExpiresAt time.Time `bson:"expiresat"`
+}
+
+func (s *Store) Get(ctx context.Context, hash string) (string, bool) {
+	var stored storedQuery
+	err := s.Collection.FindOne(ctx, bson.M{"_id": hash}).Decode(&stored)
+	if err != nil {
+		if err != mongo.ErrNoDocuments {
+			log.Printf("Failed to find persisted query %s: %v", hash, err)
+		}
+		return "", false
+	}
+	return stored.Query, true
+}
+
+func (s *Store) Add(ctx context.Context, hash string, query string) {
+	// El conteo estimado no es exacto, pero basta para acotar la colección sin
+	// recorrerla en cada registro
+	count, err := s.Collection.EstimatedDocumentCount(ctx)
+	if err != nil {
+		log.Printf("Failed to count persisted queries: %v", err)
+		return
+	}
+	if count >= s.Max {
+		log.Printf("Persisted query store is full (%d queries); %s is only kept in memory", count, hash)
+		return
+	}
+
+	// El hash identifica al texto, así que si ya existe no hay nada que actualizar
+	now := time.Now().UTC()
+	_, err = s.Collection.UpdateOne(ctx,
+		bson.M{"_id": hash},
+		bson.M{"$setOnInsert": bson.M{
+			"query":     query,
+			"createdat": now.Format(time.RFC3339),
+			"expiresat": now.Add(s.TTL),
+		}},
+		options.Update().SetUpsert(true),
+	)
+	if err != nil && !mongo.IsDuplicateKeyError(err) {
+		log.Printf("Failed to save persisted query %s: %v", hash, err)
+	}
+}
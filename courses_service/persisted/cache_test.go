@@ -0,0 +1,118 @@
+package persisted
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func TestStoreAdd(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	hash := Hash(courseQuery)
+
+	mt.Run("saves with expiration", func(mt *mtest.T) {
+		mt.AddMockResponses(
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 3}),
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}),
+		)
+		store := &Store{Collection: mt.Coll, TTL: time.Hour, Max: 10}
+
+		before := time.Now().UTC()
+		store.Add(context.Background(), hash, courseQuery)
+
+		events := mt.GetAllStartedEvents()
+		if len(events) != 2 || events[1].CommandName != "update" {
+			mt.Fatalf("Add() sent %d commands, want count and update", len(events))
+		}
+		update := events[1].Command.Lookup("updates", "0")
+		if id := update.Document().Lookup("q", "_id").StringValue(); id != hash {
+			mt.Errorf("Add() updated _id %q, want %q", id, hash)
+		}
+		if upsert, _ := update.Document().Lookup("upsert").BooleanOK(); !upsert {
+			mt.Errorf("Add() upsert = false, want true")
+		}
+		set := update.Document().Lookup("u", "$setOnInsert").Document()
+		if query := set.Lookup("query").StringValue(); query != courseQuery {
+			mt.Errorf("Add() query = %q, want %q", query, courseQuery)
+		}
+		expiresAt := set.Lookup("expiresat").Time()
+		if expiresAt.Before(before.Add(time.Hour).Truncate(time.Millisecond)) || expiresAt.After(time.Now().Add(time.Hour)) {
+			mt.Errorf("Add() expiresat = %v, want one hour from now", expiresAt)
+		}
+	})
+
+	mt.Run("full store keeps the query in memory only", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 10}))
+		store := &Store{Collection: mt.Coll, TTL: time.Hour, Max: 10}
+
+		store.Add(context.Background(), hash, courseQuery)
+
+		if events := mt.GetAllStartedEvents(); len(events) != 1 {
+			mt.Errorf("Add() sent %d commands, want only the count", len(events))
+		}
+	})
+}
+
+func TestStoreGet(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	hash := Hash(courseQuery)
+
+	mt.Run("stored query", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "coursesDB.persisted_queries", mtest.FirstBatch, bson.D{
+			{Key: "_id", Value: hash},
+			{Key: "query", Value: courseQuery},
+		}))
+		store := &Store{Collection: mt.Coll, TTL: time.Hour, Max: 10}
+
+		if query, ok := store.Get(context.Background(), hash); !ok || query != courseQuery {
+			mt.Errorf("Get() = %q, %v, want the stored query", query, ok)
+		}
+	})
+
+	mt.Run("missing query", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "coursesDB.persisted_queries", mtest.FirstBatch))
+		store := &Store{Collection: mt.Coll, TTL: time.Hour, Max: 10}
+
+		if _, ok := store.Get(context.Background(), hash); ok {
+			mt.Errorf("Get() ok = true, want false")
+		}
+	})
+}
+
+func TestCache(t *testing.T) {
+	ctx := context.Background()
+	manifest := &Manifest{operations: map[string]string{
+		Hash(courseQuery):  courseQuery,
+		Hash(coursesQuery): "",
+	}}
+
+	t.Run("manifest operations", func(t *testing.T) {
+		cache := NewCache(manifest, nil, nil)
+		if query, ok := cache.Get(ctx, Hash(courseQuery)); !ok || query != courseQuery {
+			t.Errorf("Get() = %q, %v, want the manifest body", query, ok)
+		}
+		if _, ok := cache.Get(ctx, Hash(coursesQuery)); ok {
+			t.Errorf("Get() ok = true for a manifest operation without body")
+		}
+	})
+
+	t.Run("accepted operations are kept", func(t *testing.T) {
+		cache := NewCache(manifest, nil, manifest.Contains)
+		cache.Add(ctx, Hash(coursesQuery), coursesQuery)
+		if query, ok := cache.Get(ctx, Hash(coursesQuery)); !ok || query != coursesQuery {
+			t.Errorf("Get() = %q, %v, want the added query", query, ok)
+		}
+	})
+
+	t.Run("rejected operations are not kept", func(t *testing.T) {
+		const other = `query Me { me { id } }`
+		cache := NewCache(manifest, nil, manifest.Contains)
+		cache.Add(ctx, Hash(other), other)
+		if _, ok := cache.Get(ctx, Hash(other)); ok {
+			t.Errorf("Get() ok = true for an operation missing from the manifest")
+		}
+	})
+}
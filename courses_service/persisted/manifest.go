@@ -0,0 +1,85 @@
+package persisted
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+)
+
+var hashPattern = regexp.MustCompile(`^[0-9a-f]{64}$`)
+
+// Manifest es la lista de operaciones permitidas, identificadas por el SHA-256
+// de su texto como en los persisted queries automáticos.
+type Manifest struct {
+	operations map[string]string
+}
+
+// manifestFile es el formato de manifiesto que generan las herramientas de
+// Apollo. body es opcional: sin él solo se conoce el hash y el cliente tiene
+// que enviar el texto la primera vez.
+type manifestFile struct {
+	Operations []struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+		Body string `json:"body"`
+	} `json:"operations"`
+}
+
+// LoadManifest lee un manifiesto de operaciones permitidas.
+func LoadManifest(path string) (*Manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("Error reading operation manifest: %v", err)
+	}
+	var file manifestFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("Error parsing operation manifest: %v", err)
+	}
+
+	manifest := &Manifest{operations: make(map[string]string, len(file.Operations))}
+	for _, op := range file.Operations {
+		if !hashPattern.MatchString(op.ID) {
+			return nil, fmt.Errorf("invalid hash %q for operation %q in manifest", op.ID, op.Name)
+		}
+		if op.Body != "" && Hash(op.Body) != op.ID {
+			return nil, fmt.Errorf("hash of operation %q does not match its body", op.Name)
+		}
+		manifest.operations[op.ID] = op.Body
+	}
+	return manifest, nil
+}
+
+// Len devuelve la cantidad de operaciones del manifiesto.
+func (m *Manifest) Len() int {
+	if m == nil {
+		return 0
+	}
+	return len(m.operations)
+}
+
+// Contains indica si la operación con ese hash está permitida.
+func (m *Manifest) Contains(hash string) bool {
+	if m == nil {
+		return false
+	}
+	_, ok := m.operations[hash]
+	return ok
+}
+
+// Query devuelve el texto de una operación, si el manifiesto lo incluye.
+func (m *Manifest) Query(hash string) (string, bool) {
+	if m == nil {
+		return "", false
+	}
+	query := m.operations[hash]
+	return query, query != ""
+}
+
+// Hash calcula el SHA-256 en hexadecimal del texto de una operación.
+func Hash(query string) string {
+	sum := sha256.Sum256([]byte(query))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,106 @@
+package persisted
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const (
+	courseQuery  = `query Course($id: ID!) { course(id: $id) { id title } }`
+	coursesQuery = `query Courses { courses { id } }`
+)
+
+// writeManifest guarda un manifiesto en un archivo temporal y devuelve su ruta.
+func writeManifest(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "manifest.json")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadManifest(t *testing.T) {
+	path := writeManifest(t, `{"operations": [
+		{"id": "`+Hash(courseQuery)+`", "name": "Course", "body": "`+strings.ReplaceAll(courseQuery, `"`, `\"`)+`"},
+		{"id": "`+Hash(coursesQuery)+`", "name": "Courses"}
+	]}`)
+
+	manifest, err := LoadManifest(path)
+	if err != nil {
+		t.Fatalf("LoadManifest() error = %v", err)
+	}
+	if manifest.Len() != 2 {
+		t.Errorf("Len() = %d, want 2", manifest.Len())
+	}
+	if !manifest.Contains(Hash(courseQuery)) || !manifest.Contains(Hash(coursesQuery)) {
+		t.Errorf("Contains() = false for an operation in the manifest")
+	}
+	if manifest.Contains(Hash("query { me }")) {
+		t.Errorf("Contains() = true for an operation missing from the manifest")
+	}
+	if query, ok := manifest.Query(Hash(courseQuery)); !ok || query != courseQuery {
+		t.Errorf("Query() = %q, %v, want the operation body", query, ok)
+	}
+	// Sin body solo se conoce el hash
+	if _, ok := manifest.Query(Hash(coursesQuery)); ok {
+		t.Errorf("Query() ok = true for an operation without body")
+	}
+}
+
+func TestLoadManifestErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{
+			name:    "invalid JSON",
+			content: `{"operations": [`,
+			wantErr: "Error parsing operation manifest",
+		},
+		{
+			name:    "invalid hash",
+			content: `{"operations": [{"id": "abc", "name": "Course"}]}`,
+			wantErr: `invalid hash "abc" for operation "Course"`,
+		},
+		{
+			name:    "uppercase hash",
+			content: `{"operations": [{"id": "` + strings.ToUpper(Hash(coursesQuery)) + `", "name": "Courses"}]}`,
+			wantErr: "invalid hash",
+		},
+		{
+			name:    "body does not match hash",
+			content: `{"operations": [{"id": "` + Hash(courseQuery) + `", "name": "Courses", "body": "query Courses { courses { id } }"}]}`,
+			wantErr: `hash of operation "Courses" does not match its body`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadManifest(writeManifest(t, tt.content))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("LoadManifest() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadManifestMissingFile(t *testing.T) {
+	_, err := LoadManifest(filepath.Join(t.TempDir(), "missing.json"))
+	if err == nil || !strings.Contains(err.Error(), "Error reading operation manifest") {
+		t.Errorf("LoadManifest() error = %v, want a read error", err)
+	}
+}
+
+func TestNilManifest(t *testing.T) {
+	var manifest *Manifest
+	if manifest.Len() != 0 || manifest.Contains(Hash(courseQuery)) {
+		t.Errorf("nil manifest must be empty")
+	}
+	if _, ok := manifest.Query(Hash(courseQuery)); ok {
+		t.Errorf("Query() ok = true on a nil manifest")
+	}
+}
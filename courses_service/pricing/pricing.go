@@ -0,0 +1,187 @@
+package pricing
+
+import (
+	"context"
+	"courses_service/apperrors"
+	"courses_service/graph/model"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// DefaultBaseCurrency es la moneda de Course.Price cuando BASE_CURRENCY no está definida.
+const DefaultBaseCurrency = "USD"
+
+// ErrNoRate indica que no hay tipo de cambio ni precio explícito para la moneda pedida.
+var ErrNoRate = errors.New("no exchange rate")
+
+// Rates asocia cada moneda con cuántas unidades de ella vale una unidad de la moneda base.
+type Rates map[string]float64
+
+// BaseCurrency devuelve la moneda en la que están expresados los precios base de los cursos.
+func BaseCurrency() string {
+	if code, err := NormalizeCurrency(os.Getenv("BASE_CURRENCY")); err == nil {
+		return code
+	}
+	return DefaultBaseCurrency
+}
+
+// NormalizeCurrency valida un código ISO 4217 y lo devuelve en mayúsculas.
+func NormalizeCurrency(code string) (string, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if len(code) != 3 {
+		return "", apperrors.InvalidArgument("invalid currency code %q", code)
+	}
+	for _, c := range code {
+		if c < 'A' || c > 'Z' {
+			return "", apperrors.InvalidArgument("invalid currency code %q", code)
+		}
+	}
+	return code, nil
+}
+
+// Localize calcula el precio de un curso en la moneda indicada. Un precio fijado
+// explícitamente tiene prioridad; si no existe, se convierte el precio base con la
+// tabla de tipos de cambio.
+func Localize(course *model.Course, currency string, rates Rates) (*model.LocalizedPrice, error) {
+	currency, err := NormalizeCurrency(currency)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, p := range course.Prices {
+		if p.Currency == currency {
+			return &model.LocalizedPrice{
+				Currency: currency,
+				Amount:   p.Amount,
+				Source:   model.PriceSourceExplicit,
+			}, nil
+		}
+	}
+
+	if currency == BaseCurrency() {
+		return &model.LocalizedPrice{
+			Currency: currency,
+			Amount:   course.Price,
+			Source:   model.PriceSourceBase,
+		}, nil
+	}
+
+	rate, ok := rates[currency]
+	if !ok {
+		return nil, apperrors.InvalidArgument("no exchange rate for currency %s", currency).Wrap(ErrNoRate)
+	}
+	return &model.LocalizedPrice{
+		Currency: currency,
+		Amount:   round(course.Price * rate),
+		Source:   model.PriceSourceConverted,
+		Rate:     &rate,
+	}, nil
+}
+
+// SetPrice agrega o reemplaza el precio explícito de una moneda.
+func SetPrice(prices []*model.CoursePrice, currency string, amount float64) ([]*model.CoursePrice, error) {
+	currency, err := NormalizeCurrency(currency)
+	if err != nil {
+		return nil, err
+	}
+	if amount < 0 {
+		return nil, apperrors.InvalidArgument("price for %s must not be negative", currency)
+	}
+
+	result := RemovePrice(prices, currency)
+	return append(result, &model.CoursePrice{Currency: currency, Amount: amount}), nil
+}
+
+// RemovePrice quita el precio explícito de una moneda, si existe.
+func RemovePrice(prices []*model.CoursePrice, currency string) []*model.CoursePrice {
+	result := make([]*model.CoursePrice, 0, len(prices))
+	for _, p := range prices {
+		if p.Currency != currency {
+			result = append(result, p)
+		}
+	}
+	return result
+}
+
+// ReadRatesFile lee un archivo JSON con la forma {"EUR": 0.92, "MXN": 17.1}.
+func ReadRatesFile(path string) (Rates, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("Error reading exchange rates file: %v", err)
+	}
+
+	var raw map[string]float64
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("Error parsing exchange rates file: %v", err)
+	}
+
+	rates := make(Rates, len(raw))
+	for code, rate := range raw {
+		currency, err := NormalizeCurrency(code)
+		if err != nil {
+			return nil, err
+		}
+		if rate <= 0 {
+			return nil, apperrors.InvalidArgument("exchange rate for %s must be positive", currency)
+		}
+		rates[currency] = rate
+	}
+	return rates, nil
+}
+
+// SaveRates guarda (upsert) los tipos de cambio en la colección indicada.
+func SaveRates(ctx context.Context, collection *mongo.Collection, rates Rates) ([]*model.ExchangeRate, error) {
+	now := time.Now().Format(time.RFC3339)
+	saved := make([]*model.ExchangeRate, 0, len(rates))
+
+	for currency, rate := range rates {
+		exchangeRate := model.ExchangeRate{Currency: currency, Rate: rate, UpdatedAt: now}
+		_, err := collection.ReplaceOne(ctx,
+			bson.M{"currency": currency},
+			exchangeRate,
+			options.Replace().SetUpsert(true),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("Error saving exchange rate for %s: %v", currency, err)
+		}
+		saved = append(saved, &exchangeRate)
+	}
+	return saved, nil
+}
+
+// FetchRates carga la tabla completa de tipos de cambio.
+func FetchRates(ctx context.Context, collection *mongo.Collection) ([]*model.ExchangeRate, error) {
+	cursor, err := collection.Find(ctx, bson.D{}, options.Find().SetSort(bson.D{{Key: "currency", Value: 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("Error finding exchange rates: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	var rates []*model.ExchangeRate
+	if err := cursor.All(ctx, &rates); err != nil {
+		return nil, fmt.Errorf("Error decoding exchange rates: %v", err)
+	}
+	return rates, nil
+}
+
+// RatesTable convierte la lista de tipos de cambio en un mapa por moneda.
+func RatesTable(rates []*model.ExchangeRate) Rates {
+	table := make(Rates, len(rates))
+	for _, r := range rates {
+		table[r.Currency] = r.Rate
+	}
+	return table
+}
+
+func round(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
@@ -0,0 +1,109 @@
+package pricing
+
+import (
+	"courses_service/apperrors"
+	"courses_service/graph/model"
+	"errors"
+	"testing"
+)
+
+func TestLocalize(t *testing.T) {
+	t.Setenv("BASE_CURRENCY", "USD")
+
+	course := &model.Course{
+		ID:    "c1",
+		Price: 19.99,
+		Prices: []*model.CoursePrice{
+			{Currency: "EUR", Amount: 17.5},
+			{Currency: "USD", Amount: 18},
+		},
+	}
+	rates := Rates{"EUR": 0.92, "MXN": 17.1234, "JPY": 151.3}
+
+	tests := []struct {
+		name     string
+		course   *model.Course
+		currency string
+		want     *model.LocalizedPrice
+		wantCode apperrors.Code
+		noRate   bool
+	}{
+		{
+			name:     "explicit price wins over the rate",
+			course:   course,
+			currency: "EUR",
+			want:     &model.LocalizedPrice{Currency: "EUR", Amount: 17.5, Source: model.PriceSourceExplicit},
+		},
+		{
+			name:     "explicit price wins over the base price",
+			course:   course,
+			currency: "usd",
+			want:     &model.LocalizedPrice{Currency: "USD", Amount: 18, Source: model.PriceSourceExplicit},
+		},
+		{
+			name:     "base currency",
+			course:   &model.Course{Price: 19.99},
+			currency: "USD",
+			want:     &model.LocalizedPrice{Currency: "USD", Amount: 19.99, Source: model.PriceSourceBase},
+		},
+		{
+			name:     "converted and rounded",
+			course:   course,
+			currency: " mxn ",
+			want:     &model.LocalizedPrice{Currency: "MXN", Amount: 342.3, Source: model.PriceSourceConverted, Rate: ptr(17.1234)},
+		},
+		{
+			name:     "no rate",
+			course:   course,
+			currency: "GBP",
+			wantCode: apperrors.CodeInvalidArgument,
+			noRate:   true,
+		},
+		{
+			name:     "invalid currency",
+			course:   course,
+			currency: "EURO",
+			wantCode: apperrors.CodeInvalidArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Localize(tt.course, tt.currency, rates)
+			if tt.wantCode != "" {
+				if code := apperrors.CodeOf(err); code != tt.wantCode {
+					t.Fatalf("Localize() error = %v, want code %s", err, tt.wantCode)
+				}
+				if errors.Is(err, ErrNoRate) != tt.noRate {
+					t.Errorf("errors.Is(err, ErrNoRate) = %v, want %v", !tt.noRate, tt.noRate)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Localize() error = %v", err)
+			}
+			if got.Currency != tt.want.Currency || got.Amount != tt.want.Amount || got.Source != tt.want.Source {
+				t.Errorf("Localize() = %+v, want %+v", got, tt.want)
+			}
+			if (got.Rate == nil) != (tt.want.Rate == nil) || got.Rate != nil && *got.Rate != *tt.want.Rate {
+				t.Errorf("Localize() rate = %v, want %v", got.Rate, tt.want.Rate)
+			}
+		})
+	}
+}
+
+func TestLocalizeBaseCurrencyFromEnv(t *testing.T) {
+	t.Setenv("BASE_CURRENCY", "eur")
+
+	got, err := Localize(&model.Course{Price: 10}, "EUR", Rates{})
+	if err != nil {
+		t.Fatalf("Localize() error = %v", err)
+	}
+	if got.Source != model.PriceSourceBase || got.Amount != 10 {
+		t.Errorf("Localize() = %+v, want the base price", got)
+	}
+}
+
+func ptr(v float64) *float64 {
+	return &v
+}
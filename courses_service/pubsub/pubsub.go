@@ -0,0 +1,62 @@
+package pubsub
+
+import (
+	"context"
+	"log"
+	"sync"
+)
+
+// bufferSize es la cantidad de mensajes que un suscriptor lento puede acumular
+// antes de que se descarten los nuevos.
+const bufferSize = 16
+
+// Broker reparte mensajes en memoria entre los suscriptores de cada tema.
+type Broker[T any] struct {
+	mu          sync.RWMutex
+	subscribers map[string]map[chan T]struct{}
+}
+
+func NewBroker[T any]() *Broker[T] {
+	return &Broker[T]{subscribers: map[string]map[chan T]struct{}{}}
+}
+
+// Subscribe devuelve un canal con los mensajes publicados en el tema. El canal
+// se cierra cuando termina el contexto.
+func (b *Broker[T]) Subscribe(ctx context.Context, topic string) <-chan T {
+	ch := make(chan T, bufferSize)
+
+	b.mu.Lock()
+	if b.subscribers[topic] == nil {
+		b.subscribers[topic] = map[chan T]struct{}{}
+	}
+	b.subscribers[topic][ch] = struct{}{}
+	b.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		b.mu.Lock()
+		delete(b.subscribers[topic], ch)
+		if len(b.subscribers[topic]) == 0 {
+			delete(b.subscribers, topic)
+		}
+		b.mu.Unlock()
+		close(ch)
+	}()
+
+	return ch
+}
+
+// Publish envía el mensaje a los suscriptores del tema sin bloquearse: si el
+// canal de un suscriptor está lleno, el mensaje se descarta para ese suscriptor.
+func (b *Broker[T]) Publish(topic string, message T) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for ch := range b.subscribers[topic] {
+		select {
+		case ch <- message:
+		default:
+			log.Printf("Dropping message on topic %s for a slow subscriber", topic)
+		}
+	}
+}
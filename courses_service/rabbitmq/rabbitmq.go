@@ -4,37 +4,84 @@ import (
 	"context"
 	"courses_service/graph/model"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"os"
+	"sync"
+	"time"
 
 	"github.com/streadway/amqp"
-	"go.mongodb.org/mongo-driver/bson"
-	"go.mongodb.org/mongo-driver/mongo"
-	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
-func ConnectRabbitMQ() (*amqp.Channel, error) {
+// Abrir una conexión y un canal. Cerrar el canal no cierra la conexión: quien
+// llama debe cerrar ambos.
+func ConnectRabbitMQ() (*amqp.Connection, *amqp.Channel, error) {
 	conn, err := amqp.Dial(os.Getenv("RABBITMQ_URL"))
 	if err != nil {
-		log.Fatalf("Failed to connect to RabbitMQ: %v", err)
-		return nil, err
+		return nil, nil, fmt.Errorf("Error connecting to RabbitMQ: %v", err)
 	}
 	ch, err := conn.Channel()
 	if err != nil {
-		log.Fatalf("Failed to open a channel: %v", err)
-		return nil, err
+		conn.Close()
+		return nil, nil, fmt.Errorf("Error opening a channel: %v", err)
 	}
+	return conn, ch, nil
+}
+
+// closeOnDone cierra la conexión cuando se cancela ctx, lo que termina el
+// consumo de las entregas. La función devuelta deja de esperar a ctx.
+func closeOnDone(ctx context.Context, conn *amqp.Connection) func() bool {
+	return context.AfterFunc(ctx, func() { conn.Close() })
+}
+
+// Canal compartido por las funciones que publican mensajes. Abrir una conexión
+// por mensaje era lento y, como cerrar el canal no cierra la conexión, las
+// dejaba abiertas.
+var publisher struct {
+	mu     sync.Mutex
+	conn   *amqp.Connection
+	ch     *amqp.Channel
+	closed chan *amqp.Error
+}
+
+// publishChannel devuelve el canal compartido para publicar. Si el canal o la
+// conexión se cerraron (p. ej. se reinició RabbitMQ), se abre una conexión nueva.
+func publishChannel() (*amqp.Channel, error) {
+	publisher.mu.Lock()
+	defer publisher.mu.Unlock()
+
+	if publisher.ch != nil {
+		select {
+		case <-publisher.closed:
+			publisher.conn.Close()
+			publisher.ch = nil
+		default:
+			return publisher.ch, nil
+		}
+	}
+
+	conn, err := amqp.Dial(os.Getenv("RABBITMQ_URL"))
+	if err != nil {
+		return nil, fmt.Errorf("Error connecting to RabbitMQ: %v", err)
+	}
+	ch, err := conn.Channel()
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("Error opening a channel: %v", err)
+	}
+	publisher.conn = conn
+	publisher.ch = ch
+	publisher.closed = ch.NotifyClose(make(chan *amqp.Error, 1))
 	return ch, nil
 }
 
 // Publicar un mensaje en RabbitMQ
 func PublishMessage(queueName string, body []byte) error {
-	ch, err := ConnectRabbitMQ()
+	ch, err := publishChannel()
 	if err != nil {
 		return err
 	}
-	defer ch.Close()
 
 	q, err := ch.QueueDeclare(
 		queueName, // Name of the queue
@@ -45,8 +92,7 @@ func PublishMessage(queueName string, body []byte) error {
 		nil,       // arguments
 	)
 	if err != nil {
-		log.Fatalf("Failed to declare a queue: %v", err)
-		return err
+		return fmt.Errorf("Error declaring queue %s: %v", queueName, err)
 	}
 
 	err = ch.Publish(
@@ -59,39 +105,92 @@ func PublishMessage(queueName string, body []byte) error {
 			Body:        body,
 		})
 	if err != nil {
-		log.Fatalf("Failed to publish a message: %v", err)
-		return err
+		return fmt.Errorf("Error publishing message to queue %s: %v", queueName, err)
 	}
 
 	log.Printf("Message published to queue %s: %s", queueName, body)
 	return nil
 }
 
-// Enviar los detalles de un curso específico a través de RabbitMQ
-func SendCourseDetails(courseID string) error {
-	ch, err := ConnectRabbitMQ()
+// Exchange de tipo topic donde se publican los eventos de dominio del servicio
+const EventsExchange = "courses_events"
+
+// Eventos de cursos, publicados a partir del change stream de la colección courses
+const (
+	CourseCreatedEvent = "course.created"
+	CourseUpdatedEvent = "course.updated"
+	CourseDeletedEvent = "course.deleted"
+)
+
+// Event es el sobre común de los eventos de dominio publicados en RabbitMQ.
+type Event struct {
+	Type       string      `json:"type"`
+	OccurredAt string      `json:"occurred_at"`
+	Data       interface{} `json:"data"`
+}
+
+// Publicar un evento de dominio usando su tipo como routing key
+func PublishEvent(eventType string, data interface{}) error {
+	ch, err := publishChannel()
 	if err != nil {
 		return err
 	}
-	defer ch.Close()
 
-	// Initialize MongoDB client and collection
-	client, err := mongo.Connect(context.TODO(), options.Client().ApplyURI(os.Getenv("MONGO_URI")))
+	err = ch.ExchangeDeclare(
+		EventsExchange, // name
+		"topic",        // type
+		true,           // durable
+		false,          // auto-deleted
+		false,          // internal
+		false,          // no-wait
+		nil,            // arguments
+	)
 	if err != nil {
-		return fmt.Errorf("Error connecting to MongoDB: %v", err)
+		return fmt.Errorf("Error declaring exchange %s: %v", EventsExchange, err)
 	}
-	defer client.Disconnect(context.TODO())
 
-	courseCollection := client.Database("coursesDB").Collection("courses")
+	body, err := json.Marshal(Event{
+		Type:       eventType,
+		OccurredAt: time.Now().Format(time.RFC3339),
+		Data:       data,
+	})
+	if err != nil {
+		return fmt.Errorf("Error marshaling event %s: %v", eventType, err)
+	}
 
-	// Busca el curso en la base de datos
-	var course model.Course
-	err = courseCollection.FindOne(context.TODO(), bson.M{"_id": courseID}).Decode(&course)
+	err = ch.Publish(
+		EventsExchange, // exchange
+		eventType,      // routing key
+		false,          // mandatory
+		false,          // immediate
+		amqp.Publishing{
+			ContentType: "application/json",
+			Body:        body,
+		})
 	if err != nil {
-		return fmt.Errorf("Error finding course: %v", err)
+		return fmt.Errorf("Error publishing event %s: %v", eventType, err)
 	}
 
-	courseDetails, err := json.Marshal(course)
+	log.Printf("Event %s published to exchange %s: %s", eventType, EventsExchange, body)
+	return nil
+}
+
+// CourseDetails es el mensaje que recibe el servicio de usuarios para agregar un
+// curso al carrito de UserID; los campos del curso van al mismo nivel.
+type CourseDetails struct {
+	*model.Course
+	UserID string `json:"userID"`
+}
+
+// Enviar los detalles de un curso, ya leído de la base de datos por quien llama,
+// a través de RabbitMQ
+func SendCourseDetails(course *model.Course, userID string) error {
+	ch, err := publishChannel()
+	if err != nil {
+		return err
+	}
+
+	courseDetails, err := json.Marshal(CourseDetails{Course: course, UserID: userID})
 	if err != nil {
 		return fmt.Errorf("Error marshaling course details: %v", err)
 	}
@@ -114,3 +213,276 @@ func SendCourseDetails(courseID string) error {
 	log.Printf("Course details published to queue get_course_details: %s", courseDetails)
 	return nil
 }
+
+// Cola de la que el servicio de usuarios toma los paquetes que se agregan al carrito
+const BundleDetailsQueue = "get_bundle_details"
+
+// BundleDetails es el mensaje que recibe el servicio de usuarios para agregar un
+// paquete al carrito de UserID: el precio del paquete sustituye a la suma de sus cursos.
+type BundleDetails struct {
+	Bundle  *model.Bundle   `json:"bundle"`
+	Courses []*model.Course `json:"courses"`
+	UserID  string          `json:"userID"`
+}
+
+// Enviar los detalles de un paquete de cursos a través de RabbitMQ
+func SendBundleDetails(bundle *model.Bundle, courses []*model.Course, userID string) error {
+	ch, err := publishChannel()
+	if err != nil {
+		return err
+	}
+
+	// Se declara la cola para que el mensaje no se descarte si el servicio de
+	// usuarios todavía no la creó
+	_, err = ch.QueueDeclare(
+		BundleDetailsQueue, // Name of the queue
+		false,              // durable
+		false,              // delete when unused
+		false,              // exclusive
+		false,              // no-wait
+		nil,                // arguments
+	)
+	if err != nil {
+		return fmt.Errorf("Error declaring queue %s: %v", BundleDetailsQueue, err)
+	}
+
+	bundleDetails, err := json.Marshal(BundleDetails{Bundle: bundle, Courses: courses, UserID: userID})
+	if err != nil {
+		return fmt.Errorf("Error marshaling bundle details: %v", err)
+	}
+
+	// Publicar los detalles del paquete en la cola "get_bundle_details"
+	err = ch.Publish(
+		"",
+		BundleDetailsQueue,
+		false,
+		false,
+		amqp.Publishing{
+			ContentType: "application/json",
+			Body:        bundleDetails,
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("Error publishing bundle details: %v", err)
+	}
+
+	log.Printf("Bundle details published to queue %s: %s", BundleDetailsQueue, bundleDetails)
+	return nil
+}
+
+// Cola durable de la que el worker de imágenes toma los trabajos pendientes
+const ImageJobsQueue = "image_jobs"
+
+// ImageJob pide generar las variantes redimensionadas de una imagen subida a un curso.
+type ImageJob struct {
+	CourseID string `json:"courseID"`
+	MediaID  string `json:"mediaID"`
+	Key      string `json:"key"`
+}
+
+// Encolar un trabajo de procesamiento de imagen
+func PublishImageJob(job ImageJob) error {
+	ch, err := publishChannel()
+	if err != nil {
+		return err
+	}
+
+	if err := declareImageJobsQueue(ch); err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(job)
+	if err != nil {
+		return fmt.Errorf("Error marshaling image job: %v", err)
+	}
+
+	err = ch.Publish(
+		"",             // exchange
+		ImageJobsQueue, // routing key
+		false,          // mandatory
+		false,          // immediate
+		amqp.Publishing{
+			ContentType:  "application/json",
+			DeliveryMode: amqp.Persistent,
+			Body:         body,
+		})
+	if err != nil {
+		return fmt.Errorf("Error publishing image job: %v", err)
+	}
+
+	log.Printf("Image job published to queue %s: %s", ImageJobsQueue, body)
+	return nil
+}
+
+// RetryableError es un error transitorio de un trabajo de imagen, p. ej. el
+// almacenamiento no responde: el trabajo vuelve a la cola en vez de descartarse.
+type RetryableError struct {
+	Err error
+}
+
+func (e *RetryableError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *RetryableError) Unwrap() error {
+	return e.Err
+}
+
+// Pausa antes de reencolar un trabajo, para no reintentarlo en un bucle mientras
+// dura la falla
+const requeueDelay = 5 * time.Second
+
+// Consumir los trabajos de imagen de uno en uno. Los mensajes se confirman al
+// terminar handle. Si handle devuelve un *RetryableError el mensaje se reencola;
+// con cualquier otro error se descarta para no repetir un trabajo que volvería
+// a fallar. Bloquea hasta que se cierra la conexión o se cancela ctx; en ese
+// caso cierra la conexión y devuelve ctx.Err().
+func ConsumeImageJobs(ctx context.Context, handle func(ImageJob) error) error {
+	conn, ch, err := ConnectRabbitMQ()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	defer ch.Close()
+	defer closeOnDone(ctx, conn)()
+
+	if err := declareImageJobsQueue(ch); err != nil {
+		return err
+	}
+	if err := ch.Qos(1, 0, false); err != nil {
+		return fmt.Errorf("Error setting QoS: %v", err)
+	}
+
+	deliveries, err := ch.Consume(
+		ImageJobsQueue, // queue
+		"",             // consumer
+		false,          // auto-ack
+		false,          // exclusive
+		false,          // no-local
+		false,          // no-wait
+		nil,            // arguments
+	)
+	if err != nil {
+		return fmt.Errorf("Error consuming queue %s: %v", ImageJobsQueue, err)
+	}
+
+	for delivery := range deliveries {
+		var job ImageJob
+		if err := json.Unmarshal(delivery.Body, &job); err != nil {
+			log.Printf("Invalid image job %s: %v", delivery.Body, err)
+			delivery.Nack(false, false)
+			continue
+		}
+		if err := handle(job); err != nil {
+			var retryable *RetryableError
+			if errors.As(err, &retryable) {
+				log.Printf("Image job for media %s failed, requeuing: %v", job.MediaID, err)
+				time.Sleep(requeueDelay)
+				delivery.Nack(false, true)
+				continue
+			}
+			log.Printf("Image job for media %s failed: %v", job.MediaID, err)
+			delivery.Nack(false, false)
+			continue
+		}
+		delivery.Ack(false)
+	}
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return fmt.Errorf("image job consumer closed")
+}
+
+func declareImageJobsQueue(ch *amqp.Channel) error {
+	_, err := ch.QueueDeclare(
+		ImageJobsQueue, // name
+		true,           // durable
+		false,          // delete when unused
+		false,          // exclusive
+		false,          // no-wait
+		nil,            // arguments
+	)
+	if err != nil {
+		return fmt.Errorf("Error declaring queue %s: %v", ImageJobsQueue, err)
+	}
+	return nil
+}
+
+// ReceivedEvent es un evento de dominio leído del exchange; Data queda sin
+// decodificar porque su tipo depende de Type.
+type ReceivedEvent struct {
+	Type       string          `json:"type"`
+	OccurredAt string          `json:"occurred_at"`
+	Data       json.RawMessage `json:"data"`
+}
+
+// Consumir los eventos de dominio cuyos routing keys coinciden con los patrones.
+// Cada llamada usa una cola exclusiva que se borra al desconectarse, así que
+// cada instancia del servicio recibe todos los eventos. Bloquea hasta que se
+// cierra la conexión o se cancela ctx; en ese caso cierra la conexión y
+// devuelve ctx.Err().
+func ConsumeEvents(ctx context.Context, patterns []string, handle func(ReceivedEvent)) error {
+	conn, ch, err := ConnectRabbitMQ()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	defer ch.Close()
+	defer closeOnDone(ctx, conn)()
+
+	err = ch.ExchangeDeclare(
+		EventsExchange, // name
+		"topic",        // type
+		true,           // durable
+		false,          // auto-deleted
+		false,          // internal
+		false,          // no-wait
+		nil,            // arguments
+	)
+	if err != nil {
+		return fmt.Errorf("Error declaring exchange %s: %v", EventsExchange, err)
+	}
+
+	q, err := ch.QueueDeclare(
+		"",    // name generado por el servidor
+		false, // durable
+		true,  // delete when unused
+		true,  // exclusive
+		false, // no-wait
+		nil,   // arguments
+	)
+	if err != nil {
+		return fmt.Errorf("Error declaring event queue: %v", err)
+	}
+	for _, pattern := range patterns {
+		if err := ch.QueueBind(q.Name, pattern, EventsExchange, false, nil); err != nil {
+			return fmt.Errorf("Error binding event queue to %s: %v", pattern, err)
+		}
+	}
+
+	deliveries, err := ch.Consume(
+		q.Name, // queue
+		"",     // consumer
+		true,   // auto-ack
+		true,   // exclusive
+		false,  // no-local
+		false,  // no-wait
+		nil,    // arguments
+	)
+	if err != nil {
+		return fmt.Errorf("Error consuming queue %s: %v", q.Name, err)
+	}
+
+	for delivery := range deliveries {
+		var event ReceivedEvent
+		if err := json.Unmarshal(delivery.Body, &event); err != nil {
+			log.Printf("Invalid event %s: %v", delivery.Body, err)
+			continue
+		}
+		handle(event)
+	}
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return fmt.Errorf("event consumer closed")
+}
@@ -2,17 +2,40 @@ package main
 
 import (
 	"context"
+	"expvar"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
+	"os/signal"
+	"sync"
+	"syscall"
 	"time"
 
+	"courses_service/apikeys"
+	"courses_service/apperrors"
+	"courses_service/audit"
+	"courses_service/auth"
+	"courses_service/certificates"
+	"courses_service/changestream"
 	"courses_service/graph"
+	"courses_service/graph/model"
+	"courses_service/i18n"
+	"courses_service/imaging"
+	"courses_service/limits"
+	"courses_service/persisted"
+	"courses_service/pricing"
+	"courses_service/pubsub"
+	"courses_service/storage"
 
+	"github.com/99designs/gqlgen/graphql"
 	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"github.com/99designs/gqlgen/graphql/handler/lru"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
 	"github.com/99designs/gqlgen/graphql/playground"
 	"github.com/joho/godotenv"
+	"github.com/vektah/gqlparser/v2/ast"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
@@ -44,17 +67,221 @@ func main() {
 
 	db := client.Database("coursesDB")
 	courseCollection := db.Collection("courses")
+	exchangeRateCollection := db.Collection("exchange_rates")
+	reviewCollection := db.Collection("reviews")
+	enrollmentCollection := db.Collection("enrollments")
+	certificateCollection := db.Collection("certificates")
+	bundleCollection := db.Collection("bundles")
+	learningPathCollection := db.Collection("learning_paths")
+	categoryCollection := db.Collection("categories")
+	revisionCollection := db.Collection("course_revisions")
+	auditCollection := db.Collection("audit_log")
+	apiKeyCollection := db.Collection("api_keys")
+	guardCollection := db.Collection("prerequisite_guards")
+	changeStreamCollection := db.Collection("change_streams")
+	persistedQueryCollection := db.Collection("persisted_queries")
 
 	fmt.Println("Connected to MongoDB")
 
+	// Contexto que se cancela al recibir SIGINT o SIGTERM: los consumidores de
+	// RabbitMQ cierran sus conexiones y el servidor HTTP deja de aceptar pedidos
+	shutdownCtx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	var consumers sync.WaitGroup
+
+	// Crear los índices de las colecciones
+	if err := createIndexes(context.Background(), db); err != nil {
+		log.Fatalf("Error creating indexes: %v", err)
+	}
+
+	// Cargar la clave con la que se firman los certificados
+	certificateSigner, err := certificates.LoadSigner()
+	if err != nil {
+		log.Fatalf("Error loading certificate signing key: %v", err)
+	}
+	if certificateSigner == nil {
+		log.Println("No certificate signing key configured, certificates will not be issued")
+	}
+
+	// Cargar la tabla de tipos de cambio desde archivo, si está configurado
+	if path := os.Getenv("EXCHANGE_RATES_FILE"); path != "" {
+		rates, err := pricing.ReadRatesFile(path)
+		if err != nil {
+			log.Fatalf("Error loading exchange rates: %v", err)
+		}
+		if _, err := pricing.SaveRates(context.Background(), exchangeRateCollection, rates); err != nil {
+			log.Fatalf("Error saving exchange rates: %v", err)
+		}
+		log.Printf("Loaded %d exchange rates from %s", len(rates), path)
+	}
+
+	// Configurar el almacenamiento de archivos multimedia
+	blobStore, err := storage.NewFromEnv()
+	if err != nil {
+		log.Fatalf("Error configuring media storage: %v", err)
+	}
+
+	// Iniciar el worker que genera las variantes de las imágenes subidas
+	imageConfig, err := imaging.LoadConfig()
+	if err != nil {
+		log.Fatalf("Error loading image settings: %v", err)
+	}
+	imageWorker := &imaging.Worker{
+		Config:           imageConfig,
+		BlobStore:        blobStore,
+		CourseCollection: courseCollection,
+	}
+	consumers.Add(1)
+	go func() {
+		defer consumers.Done()
+		imageWorker.Run(shutdownCtx)
+	}()
+
+	// Cargar las claves con las que se validan los tokens JWT
+	authenticator, err := auth.LoadFromEnv()
+	if err != nil {
+		log.Fatalf("Error loading JWT keys: %v", err)
+	}
+	if authenticator == nil {
+		log.Println("No JWT keys configured, all requests will be anonymous")
+	}
+
 	// Configurar el servidor GraphQL
-	srv := handler.NewDefaultServer(graph.NewExecutableSchema(graph.Config{
-		Resolvers: &graph.Resolver{DB: db, CourseCollection: courseCollection},
-	}))
+	resolver := &graph.Resolver{
+		DB:                     db,
+		CourseCollection:       courseCollection,
+		ExchangeRateCollection: exchangeRateCollection,
+		ReviewCollection:       reviewCollection,
+		EnrollmentCollection:   enrollmentCollection,
+		CertificateCollection:  certificateCollection,
+		CertificateSigner:      certificateSigner,
+		BundleCollection:       bundleCollection,
+		LearningPathCollection: learningPathCollection,
+		CategoryCollection:     categoryCollection,
+		RevisionCollection:     revisionCollection,
+		AuditCollection:        auditCollection,
+		APIKeyCollection:       apiKeyCollection,
+		GuardCollection:        guardCollection,
+		CourseEvents:           pubsub.NewBroker[*model.Course](),
+		CartEvents:             pubsub.NewBroker[*model.CartEvent](),
+		BlobStore:              blobStore,
+	}
+	apiKeys := &apikeys.Store{Collection: apiKeyCollection}
+
+	// Cargar el manifiesto de operaciones permitidas y la caché de persisted queries
+	persistedConfig, err := persisted.LoadConfig()
+	if err != nil {
+		log.Fatalf("Error loading persisted query settings: %v", err)
+	}
+	if persistedConfig.Manifest != nil {
+		log.Printf("Loaded %d allowed operations, allowlist mode %s", persistedConfig.Manifest.Len(), persistedConfig.Mode)
+	}
+
+	// Cargar los proxies de los que se acepta X-Forwarded-For
+	trustedProxies, err := audit.LoadTrustedProxies()
+	if err != nil {
+		log.Fatalf("Error loading trusted proxies: %v", err)
+	}
+
+	// Cargar los límites de profundidad y costo de las consultas
+	queryLimits, err := limits.LoadConfig()
+	if err != nil {
+		log.Fatalf("Error loading query limits: %v", err)
+	}
+
+	srv := newGraphQLServer(graph.NewExecutableSchema(graph.Config{
+		Resolvers:  resolver,
+		Complexity: graph.NewComplexity(),
+		Directives: graph.DirectiveRoot{
+			HasRole: graph.HasRole,
+		},
+	}), auth.WebsocketInit(authenticator, apiKeys), persistedConfig.NewCache(persistedQueryCollection))
+	srv.Use(&persisted.Allowlist{Manifest: persistedConfig.Manifest, Mode: persistedConfig.Mode})
+	srv.Use(&limits.Extension{Config: queryLimits})
+	auditLogger := audit.NewLogger(auditCollection)
+	srv.Use(auditLogger)
+
+	// Publicar en RabbitMQ los cambios de la colección de cursos, incluidos los
+	// hechos fuera de la API, y reenviarlos a las suscripciones
+	go changestream.NewWatcher(courseCollection, changeStreamCollection).Run(shutdownCtx)
+	consumers.Add(1)
+	go func() {
+		defer consumers.Done()
+		resolver.RunEventBridge(shutdownCtx)
+	}()
+
+	// Las métricas (expvar registra /debug/vars en http.DefaultServeMux) se
+	// sirven en un puerto interno, separado de la API pública
+	metricsAddr := os.Getenv("METRICS_ADDR")
+	if metricsAddr == "" {
+		metricsAddr = "127.0.0.1:9090"
+	}
+	go func() {
+		metricsMux := http.NewServeMux()
+		metricsMux.Handle("/debug/vars", expvar.Handler())
+		log.Printf("Serving metrics on http://%s/debug/vars", metricsAddr)
+		log.Printf("Metrics server stopped: %v", http.ListenAndServe(metricsAddr, metricsMux))
+	}()
+
+	mux := http.NewServeMux()
+	mux.Handle("/", playground.Handler("GraphQL playground", "/query"))
+	mux.Handle("/query", audit.Middleware(trustedProxies, auth.Middleware(authenticator, apiKeys, i18n.Middleware(resolver.LoaderMiddleware(srv)))))
+	mux.Handle(certificates.PathPrefix, certificates.Handler(certificateCollection))
+	if local, ok := blobStore.(*storage.LocalStore); ok {
+		mux.Handle(storage.LocalPathPrefix, local.Handler())
+	}
+
+	server := &http.Server{Addr: ":8080", Handler: mux}
+	go func() {
+		log.Printf("connect to http://localhost:8080/ for GraphQL playground")
+		if err := server.ListenAndServe(); err != http.ErrServerClosed {
+			log.Fatal(err)
+		}
+	}()
+
+	<-shutdownCtx.Done()
+	shutdownTimeout, cancelShutdown := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancelShutdown()
+	if err := server.Shutdown(shutdownTimeout); err != nil {
+		log.Printf("Error shutting down HTTP server: %v", err)
+	}
+
+	// Guardar las entradas de auditoría pendientes y esperar a que los
+	// consumidores cierren sus conexiones con RabbitMQ
+	auditLogger.Close()
+	consumers.Wait()
+	log.Println("Server stopped")
+}
+
+// newGraphQLServer configura el servidor como handler.NewDefaultServer, pero con
+// un límite de subida que admite los videos promocionales de los cursos y con
+// autenticación de las suscripciones en el inicio del websocket. Los persisted
+// queries automáticos usan apqCache.
+func newGraphQLServer(es graphql.ExecutableSchema, websocketInit transport.WebsocketInitFunc, apqCache graphql.Cache[string]) *handler.Server {
+	srv := handler.New(es)
+
+	srv.AddTransport(transport.Websocket{
+		KeepAlivePingInterval: 10 * time.Second,
+		InitFunc:              websocketInit,
+	})
+	srv.AddTransport(transport.Options{})
+	srv.AddTransport(transport.GET{})
+	srv.AddTransport(transport.POST{})
+	srv.AddTransport(transport.MultipartForm{
+		MaxUploadSize: 210 << 20,
+		MaxMemory:     32 << 20,
+	})
+
+	srv.SetQueryCache(lru.New[*ast.QueryDocument](1000))
+
+	// Presentar los errores con su código y sin detalles internos
+	srv.SetErrorPresenter(apperrors.Presenter)
+	srv.SetRecoverFunc(apperrors.Recover)
 
-	http.Handle("/", playground.Handler("GraphQL playground", "/query"))
-	http.Handle("/query", srv)
+	srv.Use(extension.Introspection{})
+	srv.Use(extension.AutomaticPersistedQuery{
+		Cache: apqCache,
+	})
 
-	log.Printf("connect to http://localhost:8080/ for GraphQL playground")
-	log.Fatal(http.ListenAndServe(":8080", nil))
+	return srv
 }
@@ -0,0 +1,131 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// LocalPathPrefix es la ruta HTTP desde la que se sirven los archivos locales por defecto.
+const LocalPathPrefix = "/media/"
+
+// LocalStore guarda los archivos en un directorio del sistema de archivos.
+type LocalStore struct {
+	dir     string
+	baseURL string
+}
+
+func NewLocalStore(dir string, baseURL string) (*LocalStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("Error creating media directory: %v", err)
+	}
+	if !strings.HasSuffix(baseURL, "/") {
+		baseURL += "/"
+	}
+	return &LocalStore{dir: dir, baseURL: baseURL}, nil
+}
+
+func (s *LocalStore) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("Error creating directory for %s: %v", key, err)
+	}
+
+	// Se escribe en un archivo temporal y se renombra para no dejar archivos a medias
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return fmt.Errorf("Error creating file for %s: %v", key, err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return fmt.Errorf("Error writing %s: %v", key, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("Error writing %s: %v", key, err)
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func (s *LocalStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, ErrNotFound
+	}
+	return f, err
+}
+
+func (s *LocalStore) Delete(ctx context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("Error deleting %s: %v", key, err)
+	}
+	return nil
+}
+
+func (s *LocalStore) URL(key string) string {
+	return s.baseURL + key
+}
+
+// Handler sirve los archivos guardados; se monta en LocalPathPrefix. Solo
+// responde con archivos guardados por Put: no lista directorios ni sirve los
+// archivos ocultos, como los temporales de una subida en curso.
+func (s *LocalStore) Handler() http.Handler {
+	return http.StripPrefix(LocalPathPrefix, http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		key := req.URL.Path
+		path, err := s.path(key)
+		if err != nil || isHiddenKey(key) {
+			http.NotFound(w, req)
+			return
+		}
+
+		f, err := os.Open(path)
+		if err != nil {
+			http.NotFound(w, req)
+			return
+		}
+		defer f.Close()
+
+		info, err := f.Stat()
+		if err != nil || !info.Mode().IsRegular() {
+			http.NotFound(w, req)
+			return
+		}
+		http.ServeContent(w, req, info.Name(), info.ModTime(), f)
+	}))
+}
+
+// isHiddenKey indica si algún segmento de la clave empieza por punto.
+func isHiddenKey(key string) bool {
+	for _, segment := range strings.Split(key, "/") {
+		if strings.HasPrefix(segment, ".") {
+			return true
+		}
+	}
+	return false
+}
+
+// path convierte la clave en una ruta dentro del directorio, rechazando claves
+// que intenten salir de él.
+func (s *LocalStore) path(key string) (string, error) {
+	clean := filepath.Clean(filepath.FromSlash(key))
+	if clean == "." || filepath.IsAbs(clean) || strings.HasPrefix(clean, "..") {
+		return "", fmt.Errorf("invalid blob key %q", key)
+	}
+	return filepath.Join(s.dir, clean), nil
+}
@@ -0,0 +1,120 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func newTestStore(t *testing.T) (*LocalStore, string) {
+	t.Helper()
+	dir := t.TempDir()
+	store, err := NewLocalStore(dir, LocalPathPrefix)
+	if err != nil {
+		t.Fatalf("NewLocalStore() error = %v", err)
+	}
+	return store, dir
+}
+
+func TestLocalStore(t *testing.T) {
+	store, dir := newTestStore(t)
+	ctx := context.Background()
+	key := "courses/c1/m1.png"
+
+	if err := store.Put(ctx, key, strings.NewReader("image"), 5, "image/png"); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	r, err := store.Get(ctx, key)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	data, _ := io.ReadAll(r)
+	r.Close()
+	if string(data) != "image" {
+		t.Errorf("Get() = %q, want %q", data, "image")
+	}
+
+	// Put no deja los archivos temporales en el directorio
+	entries, _ := os.ReadDir(filepath.Join(dir, "courses", "c1"))
+	if len(entries) != 1 {
+		t.Errorf("directory has %d files after Put, want 1", len(entries))
+	}
+
+	if got := store.URL(key); got != "/media/courses/c1/m1.png" {
+		t.Errorf("URL() = %q", got)
+	}
+
+	if err := store.Delete(ctx, key); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := store.Get(ctx, key); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get() after Delete error = %v, want ErrNotFound", err)
+	}
+	if err := store.Delete(ctx, key); err != nil {
+		t.Errorf("Delete() of a missing key error = %v", err)
+	}
+}
+
+func TestLocalStoreRejectsKeysOutsideDir(t *testing.T) {
+	store, _ := newTestStore(t)
+	ctx := context.Background()
+
+	for _, key := range []string{"../secret", "courses/../../secret", "/etc/passwd", "", "."} {
+		if err := store.Put(ctx, key, strings.NewReader("x"), 1, "text/plain"); err == nil {
+			t.Errorf("Put(%q) error = nil, want an error", key)
+		}
+		if _, err := store.Get(ctx, key); err == nil {
+			t.Errorf("Get(%q) error = nil, want an error", key)
+		}
+	}
+}
+
+func TestLocalStoreHandler(t *testing.T) {
+	store, dir := newTestStore(t)
+	if err := store.Put(context.Background(), "courses/c1/m1.png", strings.NewReader("image"), 5, "image/png"); err != nil {
+		t.Fatal(err)
+	}
+	// Un temporal de una subida en curso y un archivo fuera del directorio
+	if err := os.WriteFile(filepath.Join(dir, "courses", "c1", ".upload-123"), []byte("partial"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(filepath.Dir(dir), "secret"), []byte("secret"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path       string
+		wantStatus int
+		wantBody   string
+	}{
+		{path: "/media/courses/c1/m1.png", wantStatus: http.StatusOK, wantBody: "image"},
+		{path: "/media/courses/c1/missing.png", wantStatus: http.StatusNotFound},
+		{path: "/media/courses/c1/.upload-123", wantStatus: http.StatusNotFound},
+		{path: "/media/courses/c1/", wantStatus: http.StatusNotFound},
+		{path: "/media/courses", wantStatus: http.StatusNotFound},
+		{path: "/media/../secret", wantStatus: http.StatusNotFound},
+	}
+
+	handler := store.Handler()
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.URL.Path = tt.path
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if tt.wantBody != "" && rec.Body.String() != tt.wantBody {
+				t.Errorf("body = %q, want %q", rec.Body.String(), tt.wantBody)
+			}
+		})
+	}
+}